@@ -1,9 +1,11 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -14,8 +16,41 @@ import (
 	"task-tracker/internal/domain/repository"
 	"task-tracker/internal/domain/service"
 	"task-tracker/internal/domain/types"
+	"task-tracker/internal/storage"
 )
 
+func newBlobStore(cfg config.Config) (storage.BlobStore, error) {
+	if cfg.BlobBackend == "s3" {
+		return storage.NewS3BlobStore(storage.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+		})
+	}
+	return storage.NewLocalBlobStore(cfg.BlobDir)
+}
+
+// runPeriodically запускает фоновую задачу сразу и затем раз в every (очистка корзины, автоархивация).
+func runPeriodically(ctx context.Context, name string, every time.Duration, job func(context.Context) (int64, error)) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		n, err := job(ctx)
+		if err != nil {
+			log.Printf("ERROR %s: %v", name, err)
+		} else if n > 0 {
+			log.Printf("INFO  %s tasks=%d", name, n)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 func sanitizeDBURL(raw string) string {
 	u, err := url.Parse(raw)
 	if err != nil {
@@ -52,7 +87,13 @@ func main() {
 	}
 	log.Printf("INFO  db ping ok")
 
-	if err := gormDB.AutoMigrate(&types.User{}, &types.Task{}); err != nil {
+	if err := gormDB.AutoMigrate(&types.User{}, &types.Workspace{}, &types.Task{}, &types.RefreshToken{}, &types.APIKey{},
+		&types.WorkspaceMember{}, &types.WorkspaceInvitation{}, &types.Workflow{}, &types.Label{}, &types.TaskDependency{}, &types.Comment{}, &types.Attachment{}, &types.ChecklistItem{}, &types.TimeEntry{},
+		&types.CustomField{}, &types.TaskFieldValue{}, &types.TaskRevision{}); err != nil {
+		log.Fatalf("db migrate error: %v", err)
+	}
+	// задачи, закрытые до появления статусов, переводим в done
+	if err := gormDB.Model(&types.Task{}).Where("done AND status = ?", types.StatusTodo).Update("status", types.StatusDone).Error; err != nil {
 		log.Fatalf("db migrate error: %v", err)
 	}
 	log.Printf("INFO  db migrated")
@@ -66,7 +107,8 @@ func main() {
 	router.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PATCH, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, If-Match, If-None-Match, If-Modified-Since")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "ETag, Last-Modified") // валидаторы для If-Match / условных GET
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
 			return
@@ -80,10 +122,53 @@ func main() {
 		c.Redirect(http.StatusMovedPermanently, "/static/")
 	})	
 
+	userRepo := repository.NewUserGormRepository(gormDB)
 	taskRepo := repository.NewTaskGormRepository(gormDB)
-	taskService := service.NewTaskService(taskRepo)
+	refreshTokenRepo := repository.NewRefreshTokenGormRepository(gormDB)
+	apiKeyRepo := repository.NewAPIKeyGormRepository(gormDB)
+	workspaceRepo := repository.NewWorkspaceGormRepository(gormDB)
+	workflowRepo := repository.NewWorkflowGormRepository(gormDB)
+	labelRepo := repository.NewLabelGormRepository(gormDB)
+	commentRepo := repository.NewCommentGormRepository(gormDB)
+	attachmentRepo := repository.NewAttachmentGormRepository(gormDB)
+	checklistRepo := repository.NewChecklistGormRepository(gormDB)
+	timeEntryRepo := repository.NewTimeEntryGormRepository(gormDB)
+	customFieldRepo := repository.NewCustomFieldGormRepository(gormDB)
+	revisionRepo := repository.NewTaskRevisionGormRepository(gormDB)
+	transactor := repository.NewGormTransactor(gormDB)
+	blobStore, err := newBlobStore(cfg)
+	if err != nil {
+		log.Fatalf("blob store error: %v", err)
+	}
+	log.Printf("INFO  blob store backend=%s", cfg.BlobBackend)
+	userService := service.NewUserService(userRepo, blobStore)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, userRepo)
+	authService := service.NewAuthService(userRepo, refreshTokenRepo, cfg.JWTSecret, cfg.AccessTokenTTL, cfg.RefreshTokenTTL, cfg.AdminEmails)
+	workspaceService := service.NewWorkspaceService(workspaceRepo, userRepo, workflowRepo, blobStore, cfg.InviteTTL)
+	taskService := service.NewTaskService(taskRepo, userRepo, workspaceRepo, workflowRepo, labelRepo, checklistRepo, customFieldRepo, revisionRepo, transactor, blobStore,
+		service.WithChecklistAutoComplete(cfg.ChecklistAutoComplete), service.WithTrashRetention(cfg.TrashRetention),
+		service.WithAutoArchiveAfter(cfg.AutoArchiveAfter))
+	labelService := service.NewLabelService(labelRepo, workspaceRepo)
+	commentService := service.NewCommentService(commentRepo, taskRepo)
+	attachmentService := service.NewAttachmentService(attachmentRepo, taskRepo, workspaceRepo, blobStore, cfg.AttachmentMaxBytes)
+	timeEntryService := service.NewTimeEntryService(timeEntryRepo, taskRepo, workspaceRepo)
+	customFieldService := service.NewCustomFieldService(customFieldRepo, workspaceRepo)
+	if err := userService.PromoteAdmins(context.Background(), cfg.AdminEmails); err != nil {
+		log.Fatalf("admin promote error: %v", err)
+	}
+
 	versionHandler := handlers.NewVersionHandler(taskService)	
+	authHandler := handlers.NewAuthHandler(authService)
+	userHandler := handlers.NewUserHandler(userService)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
+	workspaceHandler := handlers.NewWorkspaceHandler(workspaceService)
 	taskHandler := handlers.NewTaskHandler(taskService)
+	labelHandler := handlers.NewLabelHandler(labelService)
+	commentHandler := handlers.NewCommentHandler(commentService)
+	attachmentHandler := handlers.NewAttachmentHandler(attachmentService)
+	timeEntryHandler := handlers.NewTimeEntryHandler(timeEntryService)
+	customFieldHandler := handlers.NewCustomFieldHandler(customFieldService)
+	batchHandler := handlers.NewBatchHandler(router) // под-запросы идут через тот же роутер
 
 	api := router.Group("/api")
 	{
@@ -100,11 +185,117 @@ func main() {
 		})
 
 		api.GET("/version", versionHandler.GetVersion)
-		api.POST("/tasks", taskHandler.Create)
-		api.GET("/tasks", taskHandler.List)
-		api.GET("/tasks/:id", taskHandler.GetByID)
-		api.PATCH("/tasks/:id", taskHandler.Update)
-		api.DELETE("/tasks/:id", taskHandler.Delete)
+
+		auth := api.Group("/auth") // публичные эндпоинты входа
+		auth.POST("/signup", authHandler.Signup)
+		auth.POST("/login", authHandler.Login)
+		auth.POST("/refresh", authHandler.Refresh)
+		auth.POST("/logout", authHandler.Logout)
+
+		private := api.Group("", middleware.Auth(authService, apiKeyService)) // дальше только с access-токеном или API-ключом
+
+		private.POST("/batch", batchHandler.Handle) // несколько запросов за один round-trip
+
+		apiKeys := private.Group("/api-keys", middleware.RequireSession()) // ключами управляем только из сессии
+		apiKeys.POST("", apiKeyHandler.Create)
+		apiKeys.GET("", apiKeyHandler.List)
+		apiKeys.DELETE("/:id", apiKeyHandler.Revoke)
+
+		usersRead := private.Group("/users", middleware.RequireScope(types.ScopeUsersRead))
+		usersRead.GET("", userHandler.List)
+		usersRead.GET("/:id", userHandler.GetByID)
+
+		usersWrite := private.Group("/users", middleware.RequireScope(types.ScopeUsersWrite))
+		usersWrite.POST("", userHandler.Create)
+		usersWrite.PATCH("/:id", userHandler.Update)
+		usersWrite.DELETE("/:id", userHandler.Delete)
+
+		workspacesRead := private.Group("/workspaces", middleware.RequireScope(types.ScopeWorkspacesRead))
+		workspacesRead.GET("", workspaceHandler.List)
+		workspacesRead.GET("/:id", workspaceHandler.GetByID)
+		workspacesRead.GET("/:id/members", workspaceHandler.Members)
+		workspacesRead.GET("/:id/workflow", workspaceHandler.GetWorkflow)
+		workspacesRead.GET("/:id/capacity", middleware.RequireScope(types.ScopeTasksRead), taskHandler.Capacity) // оценки задач — ещё и tasks:read
+		workspacesRead.GET("/:id/fields", customFieldHandler.List)
+
+		workspacesWrite := private.Group("/workspaces", middleware.RequireScope(types.ScopeWorkspacesWrite))
+		workspacesWrite.POST("", workspaceHandler.Create)
+		workspacesWrite.PATCH("/:id", workspaceHandler.Update)
+		workspacesWrite.DELETE("/:id", workspaceHandler.Delete)
+		workspacesWrite.PUT("/:id/workflow", workspaceHandler.PutWorkflow)
+		workspacesWrite.POST("/:id/fields", customFieldHandler.Create)
+		workspacesWrite.PATCH("/:id/fields/:fieldId", customFieldHandler.Update)
+		workspacesWrite.DELETE("/:id/fields/:fieldId", customFieldHandler.Delete)
+		workspacesWrite.PATCH("/:id/members/:userId", workspaceHandler.UpdateMember)
+		workspacesWrite.DELETE("/:id/members/:userId", workspaceHandler.RemoveMember)
+		workspacesWrite.POST("/:id/invitations", workspaceHandler.Invite)
+		workspacesWrite.GET("/:id/invitations", workspaceHandler.Invitations)
+		workspacesWrite.DELETE("/:id/invitations/:invitationId", workspaceHandler.RevokeInvitation)
+		private.POST("/invitations/accept", middleware.RequireScope(types.ScopeWorkspacesWrite), workspaceHandler.AcceptInvitation)
+
+		tasksRead := private.Group("/tasks", middleware.RequireScope(types.ScopeTasksRead))
+		tasksRead.GET("", taskHandler.List)
+		tasksRead.GET("/:id", taskHandler.GetByID)
+		tasksRead.GET("/:id/children", taskHandler.Children)
+		tasksRead.GET("/:id/subtree", taskHandler.Subtree)
+		tasksRead.GET("/:id/occurrences", taskHandler.Occurrences)
+		tasksRead.GET("/:id/history", taskHandler.History)
+		tasksRead.GET("/:id/comments", commentHandler.List)
+		tasksRead.GET("/:id/attachments", attachmentHandler.List)
+		tasksRead.GET("/:id/attachments/:attachmentId", attachmentHandler.Download)
+		tasksRead.GET("/:id/time-entries", timeEntryHandler.List)
+
+		tasksWrite := private.Group("/tasks", middleware.RequireScope(types.ScopeTasksWrite))
+		tasksWrite.POST("", taskHandler.Create)
+		tasksWrite.PATCH("/:id", taskHandler.Update)
+		tasksWrite.DELETE("/:id", taskHandler.Delete)
+		tasksWrite.POST("/:id/restore", taskHandler.Restore)
+		tasksWrite.POST("/:id/archive", taskHandler.Archive)
+		tasksWrite.POST("/:id/unarchive", taskHandler.Unarchive)
+		tasksWrite.POST("/:id/history/:revisionId/revert", taskHandler.Revert)
+		tasksWrite.POST("/archive-done", taskHandler.ArchiveDone)
+		tasksWrite.POST("/bulk", taskHandler.Bulk)
+		tasksWrite.PUT("/:id/labels/:labelId", taskHandler.AddLabel)
+		tasksWrite.DELETE("/:id/labels/:labelId", taskHandler.RemoveLabel)
+		tasksWrite.PUT("/:id/blocked-by/:blockerId", taskHandler.AddDependency)
+		tasksWrite.DELETE("/:id/blocked-by/:blockerId", taskHandler.RemoveDependency)
+		tasksWrite.PUT("/:id/assignees/:userId", taskHandler.Assign)
+		tasksWrite.DELETE("/:id/assignees/:userId", taskHandler.Unassign)
+		tasksWrite.PUT("/:id/watch", taskHandler.Watch)
+		tasksWrite.DELETE("/:id/watch", taskHandler.Unwatch)
+		tasksWrite.POST("/:id/comments", commentHandler.Create)
+		tasksWrite.PATCH("/:id/comments/:commentId", commentHandler.Update)
+		tasksWrite.DELETE("/:id/comments/:commentId", commentHandler.Delete)
+		tasksWrite.POST("/:id/attachments", attachmentHandler.Upload)
+		tasksWrite.DELETE("/:id/attachments/:attachmentId", attachmentHandler.Delete)
+		tasksWrite.POST("/:id/checklist", taskHandler.AddChecklistItem)
+		tasksWrite.PUT("/:id/checklist/order", taskHandler.ReorderChecklist)
+		tasksWrite.PATCH("/:id/checklist/:itemId", taskHandler.UpdateChecklistItem)
+		tasksWrite.DELETE("/:id/checklist/:itemId", taskHandler.DeleteChecklistItem)
+		tasksWrite.POST("/:id/timer/start", timeEntryHandler.StartTimer)
+		tasksWrite.POST("/:id/timer/stop", timeEntryHandler.StopTimer)
+		tasksWrite.POST("/:id/time-entries", timeEntryHandler.Create)
+		tasksWrite.DELETE("/:id/time-entries/:entryId", timeEntryHandler.Delete)
+
+		labelsRead := private.Group("/labels", middleware.RequireScope(types.ScopeTasksRead)) // метки — часть задач
+		labelsRead.GET("", labelHandler.List)
+
+		labelsWrite := private.Group("/labels", middleware.RequireScope(types.ScopeTasksWrite))
+		labelsWrite.POST("", labelHandler.Create)
+		labelsWrite.PATCH("/:id", labelHandler.Update)
+		labelsWrite.DELETE("/:id", labelHandler.Delete)
+
+		private.GET("/trash", middleware.RequireScope(types.ScopeTasksRead), taskHandler.Trash) // корзина видимых задач
+
+		reports := private.Group("/reports", middleware.RequireScope(types.ScopeTasksRead)) // отчёты по видимым задачам
+		reports.GET("/time", timeEntryHandler.Report)
+	}
+
+	go runPeriodically(context.Background(), "trash purge", cfg.TrashPurgeInterval, taskService.PurgeTrash)
+	log.Printf("INFO  trash purge retention=%s every=%s", cfg.TrashRetention, cfg.TrashPurgeInterval)
+	if cfg.AutoArchiveAfter > 0 {
+		go runPeriodically(context.Background(), "auto archive", cfg.AutoArchiveInterval, taskService.AutoArchive)
+		log.Printf("INFO  auto archive after=%s every=%s", cfg.AutoArchiveAfter, cfg.AutoArchiveInterval)
 	}
 
 	addr := ":" + cfg.Port
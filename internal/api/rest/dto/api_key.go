@@ -0,0 +1,23 @@
+package dto // DTO для API
+
+import "time" // time.Time
+
+type CreateAPIKeyRequest struct { // тело запроса на выпуск ключа
+	Name   string   `json:"name"`   // название
+	Scopes []string `json:"scopes"` // права (tasks:read, tasks:write, ...)
+}
+
+type APIKeyResponse struct { // DTO ключа (без секрета)
+	ID         uint       `json:"id"`                     // id
+	Name       string     `json:"name"`                   // название
+	Prefix     string     `json:"prefix"`                 // начало ключа
+	Scopes     []string   `json:"scopes"`                 // права
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"` // последнее использование
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`   // когда отозван
+	CreatedAt  time.Time  `json:"created_at"`             // дата создания
+}
+
+type CreatedAPIKeyResponse struct { // DTO только что выпущенного ключа
+	APIKeyResponse
+	Key string `json:"key"` // сам ключ — показывается один раз
+}
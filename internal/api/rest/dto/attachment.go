@@ -0,0 +1,14 @@
+package dto // DTO для API
+
+import "time" // time.Time
+
+type AttachmentResponse struct { // DTO вложения
+	ID          uint      `json:"id"`           // id
+	TaskID      uint      `json:"task_id"`      // задача
+	UploaderID  uint      `json:"uploader_id"`  // кто загрузил
+	Filename    string    `json:"filename"`     // имя файла
+	ContentType string    `json:"content_type"` // тип по содержимому
+	Size        int64     `json:"size"`         // байты
+	SHA256      string    `json:"sha256"`       // контрольная сумма (hex)
+	CreatedAt   time.Time `json:"created_at"`   // когда загружен
+}
@@ -0,0 +1,23 @@
+package dto // DTO для API
+
+type SignupRequest struct { // тело запроса на регистрацию
+	Email    string `json:"email"`    // email
+	Password string `json:"password"` // пароль
+}
+
+type LoginRequest struct { // тело запроса на вход
+	Email    string `json:"email"`    // email
+	Password string `json:"password"` // пароль
+}
+
+type RefreshRequest struct { // тело запроса на ротацию/выход
+	RefreshToken string `json:"refresh_token"` // refresh-токен
+}
+
+type TokenResponse struct { // DTO выданных токенов
+	AccessToken  string        `json:"access_token"`   // JWT
+	RefreshToken string        `json:"refresh_token"`  // refresh-токен
+	TokenType    string        `json:"token_type"`     // всегда "Bearer"
+	ExpiresIn    int           `json:"expires_in"`     // TTL access в секундах
+	User         *UserResponse `json:"user,omitempty"` // пользователь (signup/login)
+}
@@ -0,0 +1,18 @@
+package dto // DTO для API
+
+import "encoding/json" // сырое тело под-запроса
+
+type BatchRequestItem struct { // один под-запрос POST /batch
+	ID      string            `json:"id,omitempty"`      // имя для ссылок {{id.body.поле}}: [A-Za-z0-9_-]+, номера зарезервированы (по умолчанию — номер в массиве)
+	Method  string            `json:"method"`            // GET|POST|PUT|PATCH|DELETE
+	Path    string            `json:"path"`              // /api/... (можно с query и ссылками)
+	Headers map[string]string `json:"headers,omitempty"` // напр. If-Match (Authorization берётся из самого batch)
+	Body    json.RawMessage   `json:"body,omitempty"`    // JSON тело (строки могут ссылаться на прошлые ответы)
+}
+
+type BatchResponseItem struct { // ответ на под-запрос
+	ID      string            `json:"id"`                // имя под-запроса
+	Status  int               `json:"status"`            // HTTP статус
+	Headers map[string]string `json:"headers,omitempty"` // ETag, Last-Modified, Location
+	Body    any               `json:"body"`              // JSON ответа (не JSON — строкой, нет тела — null)
+}
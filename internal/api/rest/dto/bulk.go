@@ -0,0 +1,39 @@
+package dto // DTO для API
+
+type BulkTaskFilter struct { // выбор задач для bulk вместо списка id (как query GET /tasks)
+	WorkspaceID     *uint             `json:"workspace_id,omitempty"`     // пространство
+	Status          *string           `json:"status,omitempty"`           // статус процесса
+	Done            *bool             `json:"done,omitempty"`             // выполнена / нет
+	Labels          []string          `json:"labels,omitempty"`           // имена меток
+	LabelMatch      string            `json:"label_match,omitempty"`      // any (по умолчанию) | all
+	AssigneeID      *uint             `json:"assignee_id,omitempty"`      // исполнитель
+	CustomFields    map[string]string `json:"custom_fields,omitempty"`    // как ?cf[key]=value (нужен workspace_id)
+	IncludeArchived bool              `json:"include_archived,omitempty"` // и архивные задачи
+}
+
+type BulkTaskRequest struct { // тело POST /tasks/bulk
+	IDs       []uint          `json:"ids,omitempty"`    // задачи (до 500)
+	Filter    *BulkTaskFilter `json:"filter,omitempty"` // или все задачи под фильтром (до 500)
+	Operation string          `json:"operation"`        // update|delete|archive|add_label|reassign
+	Atomic    *bool           `json:"atomic,omitempty"` // всё или ничего (по умолчанию true); false — отчёт по каждой задаче
+
+	Update     *UpdateTaskRequest `json:"update,omitempty"`      // update: изменения, как в PATCH /tasks/:id
+	Force      bool               `json:"force,omitempty"`       // update: закрывать несмотря на блокеры
+	Children   string             `json:"children,omitempty"`    // delete: reject (по умолчанию) | orphan | cascade
+	LabelID    uint               `json:"label_id,omitempty"`    // add_label: метка
+	AssigneeID uint               `json:"assignee_id,omitempty"` // reassign: новый единственный исполнитель
+}
+
+type BulkItemResponse struct { // итог по одной задаче
+	ID      uint   `json:"id"`                // задача
+	OK      bool   `json:"ok"`                // применено
+	Error   string `json:"error,omitempty"`   // код ошибки (как в ответах API)
+	Details any    `json:"details,omitempty"` // детали ошибки
+}
+
+type BulkTaskResponse struct { // отчёт bulk-операции
+	Atomic    bool               `json:"atomic"`    // выполнено одной транзакцией
+	Succeeded int                `json:"succeeded"` // сколько задач изменено
+	Failed    int                `json:"failed"`    // сколько не удалось (в атомарном режиме всегда 0)
+	Results   []BulkItemResponse `json:"results"`   // по задачам в порядке обработки
+}
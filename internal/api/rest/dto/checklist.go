@@ -0,0 +1,24 @@
+package dto // DTO для API
+
+import "time" // time.Time
+
+type CreateChecklistItemRequest struct { // тело запроса на пункт
+	Title string `json:"title"` // текст пункта
+}
+
+type UpdateChecklistItemRequest struct { // PATCH payload
+	Title *string `json:"title,omitempty"` // переименовать
+	Done  *bool   `json:"done,omitempty"`  // отметить / снять отметку
+}
+
+type ReorderChecklistRequest struct { // новый порядок
+	ItemIDs []uint `json:"item_ids"` // все пункты в нужном порядке
+}
+
+type ChecklistItemResponse struct { // DTO пункта
+	ID       uint       `json:"id"`       // id
+	Position int        `json:"position"` // порядок
+	Title    string     `json:"title"`    // текст
+	Done     bool       `json:"done"`     // отмечен
+	DoneAt   *time.Time `json:"done_at"`  // когда отмечен
+}
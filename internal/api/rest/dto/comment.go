@@ -0,0 +1,28 @@
+package dto // DTO для API
+
+import "time" // time.Time
+
+type CreateCommentRequest struct { // тело запроса на комментарий
+	ParentID *uint  `json:"parent_id,omitempty"` // ответ на комментарий
+	Body     string `json:"body"`                // текст (markdown)
+}
+
+type UpdateCommentRequest struct { // PATCH payload
+	Body string `json:"body"` // новый текст
+}
+
+type CommentResponse struct { // DTO комментария
+	ID        uint       `json:"id"`                  // id
+	TaskID    uint       `json:"task_id"`             // задача
+	ParentID  *uint      `json:"parent_id,omitempty"` // ответ на
+	Author    TaskUser   `json:"author"`              // автор
+	Body      string     `json:"body"`                // текст ("" у удалённого)
+	Deleted   bool       `json:"deleted"`             // удалён (заглушка ветки)
+	EditedAt  *time.Time `json:"edited_at"`           // когда правили
+	CreatedAt time.Time  `json:"created_at"`          // когда написан
+}
+
+type CommentPageResponse struct { // страница комментариев
+	Items      []CommentResponse `json:"items"`                 // комментарии
+	NextCursor string            `json:"next_cursor,omitempty"` // курсор следующей страницы
+}
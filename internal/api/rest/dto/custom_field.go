@@ -0,0 +1,21 @@
+package dto // DTO для API
+
+type CreateCustomFieldRequest struct { // тело запроса на создание поля
+	Key     string   `json:"key,omitempty"`     // ключ в API (по умолчанию из name)
+	Name    string   `json:"name"`              // подпись
+	Type    string   `json:"type"`              // text|number|date|select|multi_select|user
+	Options []string `json:"options,omitempty"` // варианты select / multi_select
+}
+
+type UpdateCustomFieldRequest struct { // PATCH payload (ключ и тип не меняются)
+	Name    *string  `json:"name,omitempty"`    // новая подпись
+	Options []string `json:"options,omitempty"` // новый полный список вариантов
+}
+
+type CustomFieldResponse struct { // DTO поля
+	ID      uint     `json:"id"`      // id
+	Key     string   `json:"key"`     // ключ в custom_fields задачи и в ?cf[key]=
+	Name    string   `json:"name"`    // подпись
+	Type    string   `json:"type"`    // тип значения
+	Options []string `json:"options"` // варианты (пусто у остальных типов)
+}
@@ -0,0 +1,19 @@
+package dto // DTO для API
+
+type CreateLabelRequest struct { // тело запроса на создание метки
+	WorkspaceID *uint  `json:"workspace_id,omitempty"` // пространство (нет = личная метка)
+	Name        string `json:"name"`                   // имя
+	Color       string `json:"color,omitempty"`        // #rrggbb (по умолчанию серый)
+}
+
+type UpdateLabelRequest struct { // PATCH payload
+	Name  *string `json:"name,omitempty"`  // новое имя
+	Color *string `json:"color,omitempty"` // новый цвет
+}
+
+type LabelResponse struct { // DTO метки
+	ID          uint   `json:"id"`                     // id
+	WorkspaceID *uint  `json:"workspace_id,omitempty"` // пространство
+	Name        string `json:"name"`                   // имя
+	Color       string `json:"color"`                  // цвет
+}
@@ -0,0 +1,22 @@
+package dto // DTO для API
+
+import "encoding/json" // разбор JSON
+
+type Nullable[T any] struct { // поле PATCH: отсутствует / null / значение
+	Set   bool // ключ присутствовал в JSON
+	Value *T   // nil = явный null (сбросить)
+}
+
+func (n *Nullable[T]) UnmarshalJSON(b []byte) error { // вызывается только если ключ есть
+	n.Set = true             // ключ был
+	if string(b) == "null" { // явный null
+		n.Value = nil
+		return nil
+	}
+	var v T                                       // значение
+	if err := json.Unmarshal(b, &v); err != nil { // разобрать
+		return err
+	}
+	n.Value = &v
+	return nil
+}
@@ -0,0 +1,19 @@
+package dto // DTO для API
+
+import "time" // time.Time
+
+type RevisionResponse struct { // DTO ревизии задачи
+	ID           uint                  `json:"id"`                      // id
+	TaskID       uint                  `json:"task_id"`                 // задача
+	Action       string                `json:"action"`                  // create|update|delete|restore|archive|unarchive|revert
+	User         *TaskUser             `json:"user"`                    // кто изменил (null — пользователь удалён)
+	RevertedFrom *uint                 `json:"reverted_from,omitempty"` // к какой ревизии откатили
+	Changes      []FieldChangeResponse `json:"changes"`                 // что поменялось
+	CreatedAt    time.Time             `json:"created_at"`              // когда
+}
+
+type FieldChangeResponse struct { // изменение одного поля
+	Field  string `json:"field"`  // title, status, custom_fields.customer, ...
+	Before any    `json:"before"` // было
+	After  any    `json:"after"`  // стало
+}
@@ -3,19 +3,123 @@ package dto // DTO для API
 import "time" // time.Time
 
 type CreateTaskRequest struct { // тело запроса на создание задачи
-	UserID uint   `json:"user_id"` // владелец
-	Title  string `json:"title"`   // заголовок
+	UserID      uint       `json:"user_id,omitempty"`      // владелец (по умолчанию текущий; чужой — только админ)
+	WorkspaceID *uint      `json:"workspace_id,omitempty"` // пространство (нет = личная задача)
+	Title       string     `json:"title"`                  // заголовок
+	Description string     `json:"description,omitempty"`  // описание (markdown)
+	Priority    string     `json:"priority,omitempty"`     // low|medium|high|urgent (по умолчанию medium)
+	Status      string     `json:"status,omitempty"`       // статус процесса (по умолчанию начальный)
+	DueAt       *time.Time `json:"due_at,omitempty"`       // срок (RFC 3339)
+	Estimate    *float64   `json:"estimate,omitempty"`     // оценка в единицах пространства
+	ParentID    *uint      `json:"parent_id,omitempty"`    // родительская задача
+	AssigneeIDs []uint     `json:"assignee_ids,omitempty"` // исполнители
+	Recurrence  string     `json:"recurrence,omitempty"`   // RRULE, напр. "FREQ=WEEKLY;BYDAY=MO,TH" (нужен due_at)
+
+	CustomFields map[string]any `json:"custom_fields,omitempty"` // значения пользовательских полей по ключу
 }
 
 type TaskResponse struct { // DTO ответа задачи
-	ID        uint      `json:"id"`         // id
-	UserID    uint      `json:"user_id"`    // владелец
-	Title     string    `json:"title"`      // заголовок
-	Done      bool      `json:"done"`       // статус
-	CreatedAt time.Time `json:"created_at"` // дата создания
+	ID                uint                    `json:"id"`                           // id
+	UserID            uint                    `json:"user_id"`                      // владелец (= creator_id, для старых клиентов)
+	CreatorID         uint                    `json:"creator_id"`                   // кто создал
+	WorkspaceID       *uint                   `json:"workspace_id,omitempty"`       // пространство
+	ParentID          *uint                   `json:"parent_id"`                    // родительская задача
+	Title             string                  `json:"title"`                        // заголовок
+	Description       string                  `json:"description"`                  // описание (markdown)
+	Priority          string                  `json:"priority"`                     // приоритет
+	DueAt             *time.Time              `json:"due_at"`                       // срок
+	Estimate          *float64                `json:"estimate"`                     // оценка (points/hours — по настройке пространства)
+	Status            string                  `json:"status"`                       // статус процесса
+	Done              bool                    `json:"done"`                         // выполнена (производное от статуса)
+	CompletedAt       *time.Time              `json:"completed_at"`                 // когда выполнена
+	Labels            []LabelResponse         `json:"labels"`                       // метки
+	Progress          *TaskProgress           `json:"progress,omitempty"`           // прогресс подзадач (если они есть)
+	BlockedBy         []TaskRef               `json:"blocked_by"`                   // от каких задач зависит
+	Blocking          []TaskRef               `json:"blocking"`                     // какие задачи ждут эту
+	Assignees         []TaskUser              `json:"assignees"`                    // исполнители
+	Watchers          []TaskUser              `json:"watchers"`                     // наблюдатели
+	CommentCount      int                     `json:"comment_count"`                // сколько комментариев
+	Checklist         []ChecklistItemResponse `json:"checklist,omitempty"`          // чек-лист (в карточке задачи)
+	ChecklistProgress *TaskProgress           `json:"checklist_progress,omitempty"` // отмечено n из m пунктов
+	TrackedSeconds    int64                   `json:"tracked_seconds"`              // учтённое время (завершённые записи)
+	Recurrence        string                  `json:"recurrence,omitempty"`         // правило повторения
+	NextOccurrenceID  *uint                   `json:"next_occurrence_id,omitempty"` // следующее вхождение (после закрытия)
+	CustomFields      map[string]any          `json:"custom_fields"`                // пользовательские поля пространства (ключ -> значение)
+	ArchivedAt        *time.Time              `json:"archived_at,omitempty"`        // когда убрана в архив
+	DeletedAt         *time.Time              `json:"deleted_at,omitempty"`         // когда удалена (только в корзине)
+	Version           uint                    `json:"version"`                      // версия (ETag; If-Match при PATCH/DELETE)
+	CreatedAt         time.Time               `json:"created_at"`                   // дата создания
+	UpdatedAt         time.Time               `json:"updated_at"`                   // дата изменения
 }
 
 type UpdateTaskRequest struct { // PATCH payload
-	Title *string `json:"title,omitempty"` // менять title (если есть)
-	Done  *bool   `json:"done,omitempty"`  // менять done (если есть)
+	Title       *string             `json:"title,omitempty"`       // менять title (если есть)
+	Description *string             `json:"description,omitempty"` // менять описание (если есть)
+	Priority    *string             `json:"priority,omitempty"`    // менять приоритет (если есть)
+	DueAt       Nullable[time.Time] `json:"due_at"`                // менять срок (null = снять)
+	Estimate    Nullable[float64]   `json:"estimate"`              // менять оценку (null = снять)
+	Status      *string             `json:"status,omitempty"`      // менять статус (по процессу)
+	Done        *bool               `json:"done,omitempty"`        // менять done (старый способ: в выполненный/начальный статус)
+	ParentID    Nullable[uint]      `json:"parent_id"`             // менять родителя (null = на верхний уровень)
+	Recurrence  Nullable[string]    `json:"recurrence"`            // менять правило повторения (null = не повторять)
+
+	CustomFields map[string]any `json:"custom_fields,omitempty"` // менять значения полей (только переданные ключи, null = очистить)
+}
+
+type TaskProgress struct { // n из m подзадач выполнено
+	Done  int `json:"done"`  // выполнено
+	Total int `json:"total"` // всего прямых подзадач
+}
+
+type TaskRef struct { // краткая ссылка на задачу
+	ID     uint   `json:"id"`     // id
+	Title  string `json:"title"`  // заголовок
+	Status string `json:"status"` // статус
+	Done   bool   `json:"done"`   // выполнена
+}
+
+type TaskUser struct { // краткая ссылка на пользователя
+	ID    uint   `json:"id"`    // id
+	Email string `json:"email"` // email
+}
+
+type ArchiveDoneRequest struct { // тело POST /tasks/archive-done
+	WorkspaceID   *uint `json:"workspace_id,omitempty"`    // пространство (нет = свои личные задачи)
+	OlderThanDays *int  `json:"older_than_days,omitempty"` // выполнены больше N дней назад (по умолчанию 30)
+}
+
+type ArchiveDoneResponse struct { // итог массовой архивации
+	Archived int64 `json:"archived"` // сколько задач ушло в архив
+}
+
+type TrashItemResponse struct { // задача в корзине
+	TaskResponse
+	PurgeAt time.Time `json:"purge_at"` // когда сотрётся навсегда
+}
+
+type TaskTreeResponse struct { // задача с вложенными подзадачами
+	TaskResponse
+	Children []TaskTreeResponse `json:"children"` // подзадачи
+}
+
+type OccurrencesResponse struct { // превью серии
+	Recurrence  string      `json:"recurrence"`  // правило повторения
+	Occurrences []time.Time `json:"occurrences"` // ближайшие сроки после текущего
+}
+
+type TaskListResponse struct { // список с агрегатами (?aggregate=true)
+	Items     []TaskResponse `json:"items"`     // страница задач
+	Aggregate TaskAggregate  `json:"aggregate"` // по всей выборке, без limit/offset
+}
+
+type TaskAggregate struct { // агрегаты выборки
+	Count     int64         `json:"count"`     // задач в выборке
+	Estimates []EstimateSum `json:"estimates"` // суммы оценок по единицам
+}
+
+type EstimateSum struct { // сумма оценок в одной единице
+	Unit      string  `json:"unit"`      // points|hours
+	Estimated int64   `json:"estimated"` // задач с оценкой
+	Total     float64 `json:"total"`     // сумма оценок
+	Remaining float64 `json:"remaining"` // сумма оценок невыполненных
 }
@@ -0,0 +1,50 @@
+package dto // DTO для API
+
+import "time" // time.Time
+
+type StartTimerRequest struct { // тело запуска таймера (опц.)
+	Note string `json:"note,omitempty"` // что делаем
+}
+
+type CreateTimeEntryRequest struct { // ручная запись времени
+	StartedAt       *time.Time `json:"started_at,omitempty"` // начало (по умолчанию — закончили только что)
+	DurationSeconds int64      `json:"duration_seconds"`     // длительность, 1..86400
+	Note            string     `json:"note,omitempty"`       // что делали
+}
+
+type TimeEntryResponse struct { // DTO записи времени
+	ID              uint       `json:"id"`               // id
+	TaskID          uint       `json:"task_id"`          // задача
+	User            TaskUser   `json:"user"`             // кто работал
+	StartedAt       time.Time  `json:"started_at"`       // начало
+	EndedAt         *time.Time `json:"ended_at"`         // конец (null = таймер идёт)
+	DurationSeconds int64      `json:"duration_seconds"` // длительность (у идущего — сколько уже прошло)
+	Running         bool       `json:"running"`          // таймер идёт
+	Note            string     `json:"note"`             // что делали
+	CreatedAt       time.Time  `json:"created_at"`       // когда записано
+}
+
+type TimeReportResponse struct { // отчёт по времени за период
+	From         time.Time        `json:"from"`          // начало периода
+	To           time.Time        `json:"to"`            // конец периода (не включительно)
+	TotalSeconds int64            `json:"total_seconds"` // всего
+	ByUser       []TimeByUserRow  `json:"by_user"`       // по пользователям
+	ByLabel      []TimeByLabelRow `json:"by_label"`      // по меткам
+	ByDay        []TimeByDayRow   `json:"by_day"`        // по дням (UTC)
+}
+
+type TimeByUserRow struct { // строка отчёта по пользователю
+	User    TaskUser `json:"user"`    // пользователь
+	Seconds int64    `json:"seconds"` // сумма
+}
+
+type TimeByLabelRow struct { // строка отчёта по метке
+	LabelID uint   `json:"label_id"` // метка
+	Name    string `json:"name"`     // имя
+	Seconds int64  `json:"seconds"`  // сумма
+}
+
+type TimeByDayRow struct { // строка отчёта по дню
+	Day     string `json:"day"`     // YYYY-MM-DD
+	Seconds int64  `json:"seconds"` // сумма
+}
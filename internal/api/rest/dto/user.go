@@ -0,0 +1,19 @@
+package dto // DTO для API
+
+import "time" // time.Time
+
+type CreateUserRequest struct { // тело запроса на создание пользователя
+	Email string `json:"email"` // email
+}
+
+type UserResponse struct { // DTO ответа пользователя
+	ID        uint      `json:"id"`         // id
+	Email     string    `json:"email"`      // email
+	Role      string    `json:"role"`       // роль
+	CreatedAt time.Time `json:"created_at"` // дата создания
+}
+
+type UpdateUserRequest struct { // PATCH payload
+	Email *string `json:"email,omitempty"` // менять email (если есть)
+	Role  *string `json:"role,omitempty"`  // менять роль (только админ)
+}
@@ -0,0 +1,18 @@
+package dto // DTO для API
+
+type WorkflowStatusDTO struct { // статус процесса
+	Key  string `json:"key"`            // машинное имя
+	Name string `json:"name,omitempty"` // отображаемое имя
+	Done bool   `json:"done"`           // считается выполненной
+}
+
+type WorkflowTransitionDTO struct { // разрешённый переход
+	From string `json:"from"` // из статуса
+	To   string `json:"to"`   // в статус
+}
+
+type WorkflowDTO struct { // процесс пространства (запрос PUT и ответ)
+	Initial     string                  `json:"initial"`     // статус новых задач
+	Statuses    []WorkflowStatusDTO     `json:"statuses"`    // статусы по порядку
+	Transitions []WorkflowTransitionDTO `json:"transitions"` // разрешённые переходы
+}
@@ -0,0 +1,73 @@
+package dto // DTO для API
+
+import "time" // time.Time
+
+type WorkspaceRequest struct { // тело запроса на создание
+	Name string `json:"name"` // название
+}
+
+type UpdateWorkspaceRequest struct { // PATCH пространства
+	Name           *string  `json:"name,omitempty"`            // новое название
+	EstimateUnit   *string  `json:"estimate_unit,omitempty"`   // points|hours
+	WeeklyCapacity *float64 `json:"weekly_capacity,omitempty"` // ёмкость участника в неделю (0 = не задана)
+}
+
+type WorkspaceResponse struct { // DTO пространства
+	ID             uint      `json:"id"`              // id
+	Name           string    `json:"name"`            // название
+	EstimateUnit   string    `json:"estimate_unit"`   // единица оценок задач
+	WeeklyCapacity float64   `json:"weekly_capacity"` // ёмкость участника в неделю
+	CreatedAt      time.Time `json:"created_at"`      // дата создания
+}
+
+type WorkspaceMemberResponse struct { // DTO участника
+	UserID         uint      `json:"user_id"`         // пользователь
+	Email          string    `json:"email"`           // email
+	Role           string    `json:"role"`            // роль
+	WeeklyCapacity *float64  `json:"weekly_capacity"` // своя ёмкость (null = как у пространства)
+	JoinedAt       time.Time `json:"joined_at"`       // когда вступил
+}
+
+type UpdateMemberRequest struct { // PATCH участника
+	Role           string            `json:"role,omitempty"`  // новая роль
+	WeeklyCapacity Nullable[float64] `json:"weekly_capacity"` // своя ёмкость (null = как у пространства)
+}
+
+type CapacityResponse struct { // ёмкость участников против назначенных оценок
+	WorkspaceID uint             `json:"workspace_id"` // пространство
+	Unit        string           `json:"unit"`         // points|hours
+	WeekStart   *string          `json:"week_start"`   // понедельник недели (null = все открытые задачи)
+	Members     []CapacityMember `json:"members"`      // участники с ролью member+
+}
+
+type CapacityMember struct { // строка отчёта о ёмкости
+	User       TaskUser `json:"user"`       // участник
+	Capacity   float64  `json:"capacity"`   // ёмкость (0 = не задана)
+	Tasks      int64    `json:"tasks"`      // открытых назначенных задач
+	Assigned   float64  `json:"assigned"`   // сумма их оценок
+	Remaining  float64  `json:"remaining"`  // capacity - assigned (может быть < 0)
+	Overloaded bool     `json:"overloaded"` // назначено больше ёмкости
+}
+
+type InviteRequest struct { // тело приглашения
+	Email string `json:"email"`          // кого
+	Role  string `json:"role,omitempty"` // роль (по умолчанию member)
+}
+
+type InvitationResponse struct { // DTO приглашения (без токена)
+	ID          uint      `json:"id"`           // id
+	WorkspaceID uint      `json:"workspace_id"` // пространство
+	Email       string    `json:"email"`        // кого
+	Role        string    `json:"role"`         // роль
+	ExpiresAt   time.Time `json:"expires_at"`   // срок действия
+	CreatedAt   time.Time `json:"created_at"`   // дата создания
+}
+
+type CreatedInvitationResponse struct { // DTO только что созданного приглашения
+	InvitationResponse
+	Token string `json:"token"` // токен для принятия — показывается один раз
+}
+
+type AcceptInvitationRequest struct { // тело принятия приглашения
+	Token string `json:"token"` // токен из приглашения
+}
@@ -0,0 +1,82 @@
+package handlers // HTTP-хендлеры
+
+import (
+	"net/http" // HTTP статусы
+	"strconv"  // parse id
+
+	"github.com/gin-gonic/gin" // Gin
+
+	"task-tracker/internal/api/rest/dto" // DTO
+	"task-tracker/internal/api/rest/response"
+	"task-tracker/internal/domain/service" // сервис
+	"task-tracker/internal/domain/types"   // модели
+)
+
+type APIKeyHandler struct { // хендлер API-ключей
+	apiKeyService *service.APIKeyService // зависимость
+}
+
+func NewAPIKeyHandler(apiKeyService *service.APIKeyService) *APIKeyHandler { // конструктор
+	return &APIKeyHandler{apiKeyService: apiKeyService} // сохранить сервис
+}
+
+func toAPIKeyResponse(k *types.APIKey) dto.APIKeyResponse { // модель -> DTO
+	return dto.APIKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.ScopeList(),
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
+
+func (h *APIKeyHandler) Create(c *gin.Context) { // POST /api-keys
+	var req dto.CreateAPIKeyRequest                // тело запроса
+	if err := c.ShouldBindJSON(&req); err != nil { // распарсить JSON
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"json": "invalid"})
+		return
+	}
+
+	key, plain, err := h.apiKeyService.Create(c.Request.Context(), req.Name, req.Scopes) // выпустить ключ
+	if err != nil {                                                                      // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreatedAPIKeyResponse{ // 201 + ключ (единственный раз)
+		APIKeyResponse: toAPIKeyResponse(key),
+		Key:            plain,
+	})
+}
+
+func (h *APIKeyHandler) List(c *gin.Context) { // GET /api-keys
+	keys, err := h.apiKeyService.List(c.Request.Context()) // вызов сервиса
+	if err != nil {                                        // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	resp := make([]dto.APIKeyResponse, 0, len(keys)) // DTO список
+	for i := range keys {                            // маппинг в DTO
+		resp = append(resp, toAPIKeyResponse(&keys[i]))
+	}
+
+	c.JSON(http.StatusOK, resp) // 200 + список
+}
+
+func (h *APIKeyHandler) Revoke(c *gin.Context) { // DELETE /api-keys/:id
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id64 == 0 { // не число / 0
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"id": "invalid"})
+		return
+	}
+
+	if err := h.apiKeyService.Revoke(c.Request.Context(), uint(id64)); err != nil { // отозвать через сервис
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent) // 204 без тела
+}
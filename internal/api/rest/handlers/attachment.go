@@ -0,0 +1,132 @@
+package handlers // HTTP-хендлеры
+
+import (
+	"errors"   // errors.As
+	"mime"     // Content-Disposition
+	"net/http" // HTTP статусы
+
+	"github.com/gin-gonic/gin" // Gin
+
+	"task-tracker/internal/api/rest/dto" // DTO
+	"task-tracker/internal/api/rest/response"
+	"task-tracker/internal/domain/service" // сервис
+	"task-tracker/internal/domain/types"   // модели
+)
+
+type AttachmentHandler struct { // хендлер вложений
+	attachmentService *service.AttachmentService // зависимость
+}
+
+func NewAttachmentHandler(attachmentService *service.AttachmentService) *AttachmentHandler { // конструктор
+	return &AttachmentHandler{attachmentService: attachmentService} // сохранить сервис
+}
+
+func toAttachmentResponse(a *types.Attachment) dto.AttachmentResponse { // модель -> DTO
+	return dto.AttachmentResponse{
+		ID:          a.ID,
+		TaskID:      a.TaskID,
+		UploaderID:  a.UploaderID,
+		Filename:    a.Filename,
+		ContentType: a.ContentType,
+		Size:        a.Size,
+		SHA256:      a.SHA256,
+		CreatedAt:   a.CreatedAt,
+	}
+}
+
+func (h *AttachmentHandler) Upload(c *gin.Context) { // POST /tasks/:id/attachments (multipart, поле file)
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	maxBytes := h.attachmentService.MaxBytes()
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes+1<<20) // + запас на заголовки multipart
+
+	fh, err := c.FormFile("file") // файл из формы
+	if err != nil {
+		var tooBig *http.MaxBytesError
+		if errors.As(err, &tooBig) { // тело больше лимита
+			response.JSONError(c, http.StatusRequestEntityTooLarge, "too_large", map[string]any{"file": "too large", "max_bytes": maxBytes})
+			return
+		}
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"file": "multipart field file is required"})
+		return
+	}
+	f, err := fh.Open()
+	if err != nil {
+		response.FromServiceError(c, err)
+		return
+	}
+	defer f.Close()
+
+	a, err := h.attachmentService.Upload(c.Request.Context(), taskID, fh.Filename, fh.Size, f) // сохранить
+	if err != nil {                                                                            // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toAttachmentResponse(a)) // 201 + DTO
+}
+
+func (h *AttachmentHandler) List(c *gin.Context) { // GET /tasks/:id/attachments
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	list, err := h.attachmentService.List(c.Request.Context(), taskID) // вызов сервиса
+	if err != nil {                                                    // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	resp := make([]dto.AttachmentResponse, 0, len(list)) // DTO список
+	for i := range list {                                // маппинг в DTO
+		resp = append(resp, toAttachmentResponse(&list[i]))
+	}
+
+	c.JSON(http.StatusOK, resp) // 200 + список
+}
+
+func (h *AttachmentHandler) Download(c *gin.Context) { // GET /tasks/:id/attachments/:attachmentId
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "attachmentId")
+	if !ok {
+		return
+	}
+
+	a, body, err := h.attachmentService.Open(c.Request.Context(), taskID, id) // метаданные + поток
+	if err != nil {                                                           // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+	defer body.Close()
+
+	c.DataFromReader(http.StatusOK, a.Size, a.ContentType, body, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename}), // не открывать в браузере
+		"X-Content-Type-Options": "nosniff",                                                                     // верить нашему Content-Type
+		"ETag":                   `"` + a.SHA256 + `"`,                                                          // содержимое неизменно
+	})
+}
+
+func (h *AttachmentHandler) Delete(c *gin.Context) { // DELETE /tasks/:id/attachments/:attachmentId
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "attachmentId")
+	if !ok {
+		return
+	}
+
+	if err := h.attachmentService.Delete(c.Request.Context(), taskID, id); err != nil { // удалить через сервис
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent) // 204 без тела
+}
@@ -0,0 +1,97 @@
+package handlers // HTTP-хендлеры
+
+import (
+	"net/http" // HTTP статусы
+
+	"github.com/gin-gonic/gin" // Gin
+
+	"task-tracker/internal/api/rest/dto" // DTO
+	"task-tracker/internal/api/rest/response"
+	"task-tracker/internal/domain/service" // сервис
+	"task-tracker/internal/domain/types"   // модели
+)
+
+type AuthHandler struct { // хендлер аутентификации
+	authService *service.AuthService // зависимость
+}
+
+func NewAuthHandler(authService *service.AuthService) *AuthHandler { // конструктор
+	return &AuthHandler{authService: authService} // сохранить сервис
+}
+
+func toTokenResponse(pair *service.TokenPair, user *types.User) dto.TokenResponse { // пара токенов -> DTO
+	resp := dto.TokenResponse{
+		AccessToken:  pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(pair.ExpiresIn.Seconds()),
+	}
+	if user != nil { // при signup/login отдаём пользователя
+		u := toUserResponse(user)
+		resp.User = &u
+	}
+	return resp
+}
+
+func (h *AuthHandler) Signup(c *gin.Context) { // POST /auth/signup
+	var req dto.SignupRequest                      // тело запроса
+	if err := c.ShouldBindJSON(&req); err != nil { // распарсить JSON
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"json": "invalid"})
+		return
+	}
+
+	user, pair, err := h.authService.Signup(c.Request.Context(), req.Email, req.Password) // регистрация
+	if err != nil {                                                                       // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toTokenResponse(pair, user)) // 201 + токены
+}
+
+func (h *AuthHandler) Login(c *gin.Context) { // POST /auth/login
+	var req dto.LoginRequest                       // тело запроса
+	if err := c.ShouldBindJSON(&req); err != nil { // распарсить JSON
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"json": "invalid"})
+		return
+	}
+
+	user, pair, err := h.authService.Login(c.Request.Context(), req.Email, req.Password) // вход
+	if err != nil {                                                                      // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toTokenResponse(pair, user)) // 200 + токены
+}
+
+func (h *AuthHandler) Refresh(c *gin.Context) { // POST /auth/refresh
+	var req dto.RefreshRequest                                               // тело запроса
+	if err := c.ShouldBindJSON(&req); err != nil || req.RefreshToken == "" { // распарсить JSON
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"refresh_token": "required"})
+		return
+	}
+
+	pair, err := h.authService.Refresh(c.Request.Context(), req.RefreshToken) // ротация
+	if err != nil {                                                           // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toTokenResponse(pair, nil)) // 200 + новая пара
+}
+
+func (h *AuthHandler) Logout(c *gin.Context) { // POST /auth/logout
+	var req dto.RefreshRequest                                               // тело запроса
+	if err := c.ShouldBindJSON(&req); err != nil || req.RefreshToken == "" { // распарсить JSON
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"refresh_token": "required"})
+		return
+	}
+
+	if err := h.authService.Logout(c.Request.Context(), req.RefreshToken); err != nil { // отозвать
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent) // 204 без тела
+}
@@ -0,0 +1,256 @@
+package handlers // HTTP-хендлеры
+
+import (
+	"bytes"             // тело под-запроса
+	"encoding/json"     // тела и ссылки
+	"net/http"          // HTTP статусы
+	"net/http/httptest" // ответ под-запроса в память
+	"net/url"           // разбор path
+	"path"              // Clean
+	"regexp"            // {{id.body.поле}}
+	"slices"            // Contains
+	"strconv"           // индексы
+	"strings"           // разбор ссылок
+
+	"github.com/gin-gonic/gin" // Gin
+
+	"task-tracker/internal/api/rest/dto"      // DTO
+	"task-tracker/internal/api/rest/response" // JSON ошибки
+)
+
+const (
+	maxBatchRequests = 20      // под-запросов в одном batch
+	maxBatchBytes    = 1 << 20 // тело batch целиком
+)
+
+var (
+	batchMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	batchHeaders = []string{"ETag", "Last-Modified", "Location"} // какие заголовки ответа отдаём клиенту
+	batchID      = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)        // то же, что допускает batchRef
+	batchRef     = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_-]+)\.body((?:\.[A-Za-z0-9_-]+)*)\s*\}\}`)
+)
+
+type BatchHandler struct { // хендлер POST /batch
+	router http.Handler // тот же gin-роутер: под-запросы проходят все middleware и проверки прав
+}
+
+func NewBatchHandler(router http.Handler) *BatchHandler { // конструктор
+	return &BatchHandler{router: router}
+}
+
+type batchRefFailure struct { // ссылку не удалось подставить
+	status int    // 424 — упал тот под-запрос, 400 — нет такого поля
+	code   string // код ошибки
+	ref    string // сама ссылка
+}
+
+func (h *BatchHandler) Handle(c *gin.Context) { // POST /batch
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBatchBytes)
+	var items []dto.BatchRequestItem
+	if err := c.ShouldBindJSON(&items); err != nil {
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"json": "must be an array of {method, path, body}"})
+		return
+	}
+	if len(items) == 0 || len(items) > maxBatchRequests {
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"requests": "must be 1..20"})
+		return
+	}
+	for i := range items { // имена и методы проверяем до выполнения — batch не должен отработать наполовину из-за опечатки
+		field := "requests." + strconv.Itoa(i) + ".id"
+		switch {
+		case items[i].ID == "":
+			items[i].ID = strconv.Itoa(i)
+		case !batchID.MatchString(items[i].ID): // иначе на него не сослаться
+			response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{field: "must match [A-Za-z0-9_-]+"})
+			return
+		case strings.Trim(items[i].ID, "0123456789") == "" && items[i].ID != strconv.Itoa(i): // номера — имена по умолчанию, чужой занимать нельзя
+			response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{field: "numeric ids are reserved for array positions"})
+			return
+		}
+		items[i].Method = strings.ToUpper(items[i].Method)
+		if !slices.Contains(batchMethods, items[i].Method) {
+			response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"requests." + strconv.Itoa(i) + ".method": "must be " + strings.Join(batchMethods, "|")})
+			return
+		}
+		for _, prev := range items[:i] {
+			if prev.ID == items[i].ID {
+				response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{field: "duplicate"})
+				return
+			}
+		}
+	}
+
+	results := make([]dto.BatchResponseItem, 0, len(items))
+	done := make(map[string]dto.BatchResponseItem, len(items)) // ответы для ссылок
+	for _, item := range items {                               // по порядку: ссылаться можно только назад
+		res := h.dispatch(c, item, done)
+		results = append(results, res)
+		done[item.ID] = res
+	}
+	c.JSON(http.StatusOK, results) // 200 + ответы в порядке запросов
+}
+
+func (h *BatchHandler) dispatch(c *gin.Context, item dto.BatchRequestItem, done map[string]dto.BatchResponseItem) dto.BatchResponseItem { // выполнить один под-запрос
+	fail := func(status int, code string, details any) dto.BatchResponseItem {
+		return dto.BatchResponseItem{ID: item.ID, Status: status, Body: response.ErrorResponse{Error: code, Details: details}}
+	}
+
+	target, rerr := resolveRefsInString(item.Path, done)
+	if rerr != nil {
+		return fail(rerr.status, rerr.code, map[string]string{"ref": rerr.ref})
+	}
+	u, err := url.Parse(target)
+	if err != nil || u.IsAbs() || u.Host != "" || !strings.HasPrefix(path.Clean(u.Path), "/api/") {
+		return fail(http.StatusBadRequest, "validation_error", map[string]string{"path": "must be an /api/... path"})
+	}
+	if p := path.Clean(u.Path); p == "/api/batch" || strings.HasPrefix(p, "/api/batch/") { // без рекурсии
+		return fail(http.StatusBadRequest, "validation_error", map[string]string{"path": "batch cannot be nested"})
+	}
+
+	var body []byte
+	if len(item.Body) > 0 && string(item.Body) != "null" {
+		dec := json.NewDecoder(bytes.NewReader(item.Body))
+		dec.UseNumber() // id остаются целыми
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return fail(http.StatusBadRequest, "validation_error", map[string]string{"body": "invalid JSON"})
+		}
+		if v, rerr = resolveRefs(v, done); rerr != nil {
+			return fail(rerr.status, rerr.code, map[string]string{"ref": rerr.ref})
+		}
+		if body, err = json.Marshal(v); err != nil {
+			return fail(http.StatusBadRequest, "validation_error", map[string]string{"body": "invalid JSON"})
+		}
+	}
+
+	req, err := http.NewRequestWithContext(c.Request.Context(), item.Method, u.RequestURI(), bytes.NewReader(body))
+	if err != nil {
+		return fail(http.StatusBadRequest, "validation_error", map[string]string{"path": "invalid"})
+	}
+	req.RemoteAddr = c.Request.RemoteAddr
+	for k, v := range item.Headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Authorization", c.GetHeader("Authorization")) // под-запрос от имени того же клиента (и с теми же правами ключа)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	rec := httptest.NewRecorder()
+	h.router.ServeHTTP(rec, req) // весь путь gin: auth, scopes, хендлер
+
+	res := dto.BatchResponseItem{ID: item.ID, Status: rec.Code}
+	for _, name := range batchHeaders {
+		if v := rec.Header().Get(name); v != "" {
+			if res.Headers == nil {
+				res.Headers = make(map[string]string, len(batchHeaders))
+			}
+			res.Headers[name] = v
+		}
+	}
+	switch raw := rec.Body.Bytes(); {
+	case len(raw) == 0: // 204 / 304
+	case json.Valid(raw):
+		res.Body = json.RawMessage(raw)
+	default: // файлы и прочее — строкой
+		res.Body = string(raw)
+	}
+	return res
+}
+
+func resolveRefs(v any, done map[string]dto.BatchResponseItem) (any, *batchRefFailure) { // подставить ссылки во все строки JSON
+	switch t := v.(type) {
+	case string:
+		if m := batchRef.FindStringSubmatch(t); m != nil && m[0] == strings.TrimSpace(t) { // строка целиком — ссылка: значение как есть (число остаётся числом)
+			return lookupRef(m, done)
+		}
+		return resolveRefsInString(t, done)
+	case []any:
+		for i := range t {
+			r, err := resolveRefs(t[i], done)
+			if err != nil {
+				return nil, err
+			}
+			t[i] = r
+		}
+	case map[string]any:
+		for k := range t {
+			r, err := resolveRefs(t[k], done)
+			if err != nil {
+				return nil, err
+			}
+			t[k] = r
+		}
+	}
+	return v, nil
+}
+
+func resolveRefsInString(s string, done map[string]dto.BatchResponseItem) (string, *batchRefFailure) { // ссылки внутри строки (path, текст)
+	var rerr *batchRefFailure
+	out := batchRef.ReplaceAllStringFunc(s, func(ref string) string {
+		if rerr != nil {
+			return ref
+		}
+		v, err := lookupRef(batchRef.FindStringSubmatch(ref), done)
+		if err != nil {
+			rerr = err
+			return ref
+		}
+		switch t := v.(type) {
+		case string:
+			return t
+		case json.Number:
+			return t.String()
+		case bool:
+			return strconv.FormatBool(t)
+		}
+		rerr = &batchRefFailure{status: http.StatusBadRequest, code: "validation_error", ref: ref} // объект в строку не вставить
+		return ref
+	})
+	if rerr != nil {
+		return "", rerr
+	}
+	return out, nil
+}
+
+func lookupRef(m []string, done map[string]dto.BatchResponseItem) (any, *batchRefFailure) { // {{id.body.a.0.b}} -> значение из ответа
+	ref := m[0]
+	prev, ok := done[m[1]]
+	if !ok { // нет такого или ещё не выполнен
+		return nil, &batchRefFailure{status: http.StatusBadRequest, code: "validation_error", ref: ref}
+	}
+	if prev.Status >= http.StatusBadRequest { // зависимость упала — этот под-запрос не выполняем
+		return nil, &batchRefFailure{status: http.StatusFailedDependency, code: "failed_dependency", ref: ref}
+	}
+	raw, ok := prev.Body.(json.RawMessage)
+	if !ok {
+		return nil, &batchRefFailure{status: http.StatusBadRequest, code: "validation_error", ref: ref}
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var cur any
+	if err := dec.Decode(&cur); err != nil {
+		return nil, &batchRefFailure{status: http.StatusBadRequest, code: "validation_error", ref: ref}
+	}
+	for _, key := range strings.Split(strings.TrimPrefix(m[2], "."), ".") {
+		if key == "" { // {{id.body}} — всё тело
+			break
+		}
+		switch t := cur.(type) {
+		case map[string]any:
+			cur, ok = t[key]
+		case []any:
+			i, err := strconv.Atoi(key)
+			ok = err == nil && i >= 0 && i < len(t)
+			if ok {
+				cur = t[i]
+			}
+		default:
+			ok = false
+		}
+		if !ok {
+			return nil, &batchRefFailure{status: http.StatusBadRequest, code: "validation_error", ref: ref}
+		}
+	}
+	return cur, nil
+}
@@ -0,0 +1,143 @@
+package handlers // HTTP-хендлеры
+
+import (
+	"net/http" // HTTP статусы
+	"strconv"  // parse limit/parent_id
+
+	"github.com/gin-gonic/gin" // Gin
+
+	"task-tracker/internal/api/rest/dto" // DTO
+	"task-tracker/internal/api/rest/response"
+	"task-tracker/internal/domain/service" // сервис
+	"task-tracker/internal/domain/types"   // модели
+)
+
+type CommentHandler struct { // хендлер комментариев
+	commentService *service.CommentService // зависимость
+}
+
+func NewCommentHandler(commentService *service.CommentService) *CommentHandler { // конструктор
+	return &CommentHandler{commentService: commentService} // сохранить сервис
+}
+
+func toCommentResponse(c *types.Comment) dto.CommentResponse { // модель -> DTO
+	resp := dto.CommentResponse{
+		ID:        c.ID,
+		TaskID:    c.TaskID,
+		ParentID:  c.ParentID,
+		Author:    dto.TaskUser{ID: c.AuthorID},
+		Body:      c.Body,
+		Deleted:   c.DeletedAt != nil,
+		EditedAt:  c.EditedAt,
+		CreatedAt: c.CreatedAt,
+	}
+	if c.Author != nil { // подгружен
+		resp.Author.Email = c.Author.Email
+	}
+	return resp
+}
+
+func (h *CommentHandler) Create(c *gin.Context) { // POST /tasks/:id/comments
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.CreateCommentRequest               // тело запроса
+	if err := c.ShouldBindJSON(&req); err != nil { // распарсить JSON
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"json": "invalid"})
+		return
+	}
+
+	comment, err := h.commentService.Create(c.Request.Context(), taskID, req.ParentID, req.Body) // написать
+	if err != nil {                                                                              // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toCommentResponse(comment)) // 201 + DTO
+}
+
+func (h *CommentHandler) List(c *gin.Context) { // GET /tasks/:id/comments
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var parentID *uint                      // одна ветка (optional)
+	if s := c.Query("parent_id"); s != "" { // ?parent_id=...
+		v, err := strconv.ParseUint(s, 10, 64)
+		if err != nil || v == 0 {
+			response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"parent_id": "invalid"})
+			return
+		}
+		id := uint(v)
+		parentID = &id
+	}
+
+	limit := 0                          // дефолт решает сервис
+	if s := c.Query("limit"); s != "" { // ?limit=...
+		v, err := strconv.Atoi(s)
+		if err != nil || v <= 0 {
+			response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"limit": "invalid"})
+			return
+		}
+		limit = v
+	}
+
+	page, err := h.commentService.List(c.Request.Context(), taskID, parentID, c.Query("cursor"), limit) // страница
+	if err != nil {                                                                                     // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	resp := dto.CommentPageResponse{Items: make([]dto.CommentResponse, 0, len(page.Items)), NextCursor: page.NextCursor}
+	for i := range page.Items { // маппинг в DTO
+		resp.Items = append(resp.Items, toCommentResponse(&page.Items[i]))
+	}
+
+	c.JSON(http.StatusOK, resp) // 200 + страница
+}
+
+func (h *CommentHandler) Update(c *gin.Context) { // PATCH /tasks/:id/comments/:commentId
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "commentId")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateCommentRequest               // тело PATCH
+	if err := c.ShouldBindJSON(&req); err != nil { // парсим JSON
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"json": "invalid"})
+		return
+	}
+
+	comment, err := h.commentService.Update(c.Request.Context(), taskID, id, req.Body) // правка
+	if err != nil {                                                                    // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toCommentResponse(comment)) // 200 + DTO
+}
+
+func (h *CommentHandler) Delete(c *gin.Context) { // DELETE /tasks/:id/comments/:commentId
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "commentId")
+	if !ok {
+		return
+	}
+
+	if err := h.commentService.Delete(c.Request.Context(), taskID, id); err != nil { // удалить через сервис
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent) // 204 без тела
+}
@@ -0,0 +1,113 @@
+package handlers // HTTP-хендлеры
+
+import (
+	"net/http" // HTTP статусы
+
+	"github.com/gin-gonic/gin" // Gin
+
+	"task-tracker/internal/api/rest/dto" // DTO
+	"task-tracker/internal/api/rest/response"
+	"task-tracker/internal/domain/service" // сервис
+	"task-tracker/internal/domain/types"   // модели
+)
+
+type CustomFieldHandler struct { // хендлер пользовательских полей
+	fieldService *service.CustomFieldService // зависимость
+}
+
+func NewCustomFieldHandler(fieldService *service.CustomFieldService) *CustomFieldHandler { // конструктор
+	return &CustomFieldHandler{fieldService: fieldService} // сохранить сервис
+}
+
+func toCustomFieldResponse(f *types.CustomField) dto.CustomFieldResponse { // модель -> DTO
+	options := f.Options
+	if options == nil { // всегда массив, не null
+		options = []string{}
+	}
+	return dto.CustomFieldResponse{ID: f.ID, Key: f.Key, Name: f.Name, Type: f.Type, Options: options}
+}
+
+func (h *CustomFieldHandler) List(c *gin.Context) { // GET /workspaces/:id/fields
+	wsID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	fields, err := h.fieldService.List(c.Request.Context(), wsID) // вызов сервиса
+	if err != nil {                                               // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	resp := make([]dto.CustomFieldResponse, 0, len(fields)) // DTO список
+	for i := range fields {                                 // маппинг в DTO
+		resp = append(resp, toCustomFieldResponse(&fields[i]))
+	}
+
+	c.JSON(http.StatusOK, resp) // 200 + список
+}
+
+func (h *CustomFieldHandler) Create(c *gin.Context) { // POST /workspaces/:id/fields
+	wsID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.CreateCustomFieldRequest           // тело запроса
+	if err := c.ShouldBindJSON(&req); err != nil { // распарсить JSON
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"json": "invalid"})
+		return
+	}
+
+	in := service.CustomFieldInput{Key: req.Key, Name: req.Name, Type: req.Type, Options: req.Options}
+	field, err := h.fieldService.Create(c.Request.Context(), wsID, in) // создать
+	if err != nil {                                                    // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toCustomFieldResponse(field)) // 201 + DTO
+}
+
+func (h *CustomFieldHandler) Update(c *gin.Context) { // PATCH /workspaces/:id/fields/:fieldId
+	wsID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	fieldID, ok := parseIDParam(c, "fieldId")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateCustomFieldRequest           // тело PATCH
+	if err := c.ShouldBindJSON(&req); err != nil { // парсим JSON
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"json": "invalid"})
+		return
+	}
+
+	field, err := h.fieldService.Update(c.Request.Context(), wsID, fieldID, req.Name, req.Options) // вызов сервиса
+	if err != nil {                                                                                // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toCustomFieldResponse(field)) // 200 + DTO
+}
+
+func (h *CustomFieldHandler) Delete(c *gin.Context) { // DELETE /workspaces/:id/fields/:fieldId
+	wsID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	fieldID, ok := parseIDParam(c, "fieldId")
+	if !ok {
+		return
+	}
+
+	if err := h.fieldService.Delete(c.Request.Context(), wsID, fieldID); err != nil { // удалить вместе со значениями
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent) // 204 без тела
+}
@@ -0,0 +1,102 @@
+package handlers // HTTP-хендлеры
+
+import (
+	"net/http" // HTTP статусы
+	"strconv"  // parse workspace_id
+
+	"github.com/gin-gonic/gin" // Gin
+
+	"task-tracker/internal/api/rest/dto" // DTO
+	"task-tracker/internal/api/rest/response"
+	"task-tracker/internal/domain/service" // сервис
+	"task-tracker/internal/domain/types"   // модели
+)
+
+type LabelHandler struct { // хендлер меток
+	labelService *service.LabelService // зависимость
+}
+
+func NewLabelHandler(labelService *service.LabelService) *LabelHandler { // конструктор
+	return &LabelHandler{labelService: labelService} // сохранить сервис
+}
+
+func toLabelResponse(l *types.Label) dto.LabelResponse { // модель -> DTO
+	return dto.LabelResponse{ID: l.ID, WorkspaceID: l.WorkspaceID, Name: l.Name, Color: l.Color}
+}
+
+func (h *LabelHandler) Create(c *gin.Context) { // POST /labels
+	var req dto.CreateLabelRequest                 // тело запроса
+	if err := c.ShouldBindJSON(&req); err != nil { // распарсить JSON
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"json": "invalid"})
+		return
+	}
+
+	label, err := h.labelService.Create(c.Request.Context(), req.WorkspaceID, req.Name, req.Color) // создать
+	if err != nil {                                                                                // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toLabelResponse(label)) // 201 + DTO
+}
+
+func (h *LabelHandler) List(c *gin.Context) { // GET /labels
+	var workspaceID *uint                      // фильтр по пространству
+	if s := c.Query("workspace_id"); s != "" { // ?workspace_id=...
+		v, err := strconv.ParseUint(s, 10, 64) // парсим id
+		if err != nil || v == 0 {              // не число / 0
+			response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"workspace_id": "invalid"})
+			return
+		}
+		id := uint(v)
+		workspaceID = &id
+	}
+
+	labels, err := h.labelService.List(c.Request.Context(), workspaceID) // вызов сервиса
+	if err != nil {                                                      // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	resp := make([]dto.LabelResponse, 0, len(labels)) // DTO список
+	for i := range labels {                           // маппинг в DTO
+		resp = append(resp, toLabelResponse(&labels[i]))
+	}
+
+	c.JSON(http.StatusOK, resp) // 200 + список
+}
+
+func (h *LabelHandler) Update(c *gin.Context) { // PATCH /labels/:id
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateLabelRequest                 // тело PATCH
+	if err := c.ShouldBindJSON(&req); err != nil { // парсим JSON
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"json": "invalid"})
+		return
+	}
+
+	label, err := h.labelService.Update(c.Request.Context(), id, req.Name, req.Color) // вызов сервиса
+	if err != nil {                                                                   // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toLabelResponse(label)) // 200 + DTO
+}
+
+func (h *LabelHandler) Delete(c *gin.Context) { // DELETE /labels/:id
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.labelService.Delete(c.Request.Context(), id); err != nil { // удалить через сервис
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent) // 204 без тела
+}
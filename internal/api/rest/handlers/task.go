@@ -1,15 +1,20 @@
 package handlers // HTTP-хендлеры
 
 import (
-	// errors.Is
+	"errors"   // errors.Is
+	"io"       // пустое тело
 	"net/http" // HTTP статусы
 	"strconv"  // parse id
+	"strings"  // If-Match
+	"time"     // ?week=
 
 	"github.com/gin-gonic/gin" // Gin
 
 	"task-tracker/internal/api/rest/dto" // DTO
 	"task-tracker/internal/api/rest/response"
-	"task-tracker/internal/domain/service" // сервис
+	"task-tracker/internal/domain/middleware" // текущий пользователь
+	"task-tracker/internal/domain/service"    // сервис
+	"task-tracker/internal/domain/types"      // фильтры
 )
 
 type TaskHandler struct { // хендлер задач
@@ -20,9 +25,82 @@ func NewTaskHandler(taskService *service.TaskService) *TaskHandler { // конс
 	return &TaskHandler{taskService: taskService} // сохранить сервис
 }
 
-func toTaskResponse(t any) dto.TaskResponse { // маппер (пока заглушка)
-	// будем приводить в местах вызова, чтобы проще читалось
-	return dto.TaskResponse{}
+func toTaskRefs(refs []types.TaskRef) []dto.TaskRef { // ссылки -> DTO (всегда массив, не null)
+	out := make([]dto.TaskRef, 0, len(refs))
+	for _, r := range refs {
+		out = append(out, dto.TaskRef{ID: r.ID, Title: r.Title, Status: r.Status, Done: r.Done})
+	}
+	return out
+}
+
+func toTaskUsers(users []types.User) []dto.TaskUser { // пользователи -> DTO (всегда массив, не null)
+	out := make([]dto.TaskUser, 0, len(users))
+	for _, u := range users {
+		out = append(out, dto.TaskUser{ID: u.ID, Email: u.Email})
+	}
+	return out
+}
+
+func toTaskResponse(t *types.Task) dto.TaskResponse { // модель -> DTO
+	labels := make([]dto.LabelResponse, 0, len(t.Labels)) // всегда массив, не null
+	for i := range t.Labels {
+		labels = append(labels, toLabelResponse(&t.Labels[i]))
+	}
+	var checklist []dto.ChecklistItemResponse // nil в списках — поле не выводится
+	for _, item := range t.Checklist {
+		checklist = append(checklist, dto.ChecklistItemResponse{ID: item.ID, Position: item.Position, Title: item.Title, Done: item.Done, DoneAt: item.DoneAt})
+	}
+	var checklistProgress *dto.TaskProgress
+	if t.ChecklistProgress != nil { // есть чек-лист
+		checklistProgress = &dto.TaskProgress{Done: t.ChecklistProgress.Done, Total: t.ChecklistProgress.Total}
+	}
+	customFields := make(map[string]any, len(t.Fields)) // всегда объект, не null
+	for _, v := range t.Fields {
+		if v.Field != nil {
+			customFields[v.Field.Key] = v.Value(v.Field.Type)
+		}
+	}
+	var deletedAt *time.Time
+	if t.DeletedAt.Valid { // в корзине
+		deletedAt = &t.DeletedAt.Time
+	}
+	var progress *dto.TaskProgress
+	if t.Progress != nil { // есть подзадачи
+		progress = &dto.TaskProgress{Done: t.Progress.Done, Total: t.Progress.Total}
+	}
+	return dto.TaskResponse{
+		ID:                t.ID,
+		UserID:            t.UserID,
+		CreatorID:         t.UserID,
+		WorkspaceID:       t.WorkspaceID,
+		ParentID:          t.ParentID,
+		Title:             t.Title,
+		Description:       t.Description,
+		Priority:          t.Priority,
+		DueAt:             t.DueAt,
+		Estimate:          t.Estimate,
+		Status:            t.Status,
+		Done:              t.Done,
+		CompletedAt:       t.CompletedAt,
+		Labels:            labels,
+		Progress:          progress,
+		BlockedBy:         toTaskRefs(t.BlockedBy),
+		Blocking:          toTaskRefs(t.Blocking),
+		Assignees:         toTaskUsers(t.Assignees),
+		Watchers:          toTaskUsers(t.Watchers),
+		CommentCount:      t.CommentCount,
+		Checklist:         checklist,
+		ChecklistProgress: checklistProgress,
+		TrackedSeconds:    t.TrackedSeconds,
+		Recurrence:        t.Recurrence,
+		NextOccurrenceID:  t.NextOccurrenceID,
+		CustomFields:      customFields,
+		Version:           t.Version,
+		ArchivedAt:        t.ArchivedAt,
+		DeletedAt:         deletedAt,
+		CreatedAt:         t.CreatedAt,
+		UpdatedAt:         t.UpdatedAt,
+	}
 }
 
 func (h *TaskHandler) Create(c *gin.Context) { // POST /tasks
@@ -32,31 +110,116 @@ func (h *TaskHandler) Create(c *gin.Context) { // POST /tasks
 		return
 	}
 
-	task, err := h.taskService.Create(c.Request.Context(), req.UserID, req.Title) // создать задачу
-	if err != nil {                                                               // обработка ошибок
+	task, err := h.taskService.Create(c.Request.Context(), service.CreateTaskInput{ // создать задачу (владелец проверяется сервисом)
+		UserID:      req.UserID,
+		WorkspaceID: req.WorkspaceID,
+		Title:       req.Title,
+		Description: req.Description,
+		Priority:    req.Priority,
+		Status:      req.Status,
+		DueAt:       req.DueAt,
+		Estimate:    req.Estimate,
+		ParentID:    req.ParentID,
+		AssigneeIDs: req.AssigneeIDs,
+		Recurrence:  req.Recurrence,
+
+		CustomFields: req.CustomFields,
+	})
+	if err != nil { // обработка ошибок
 		response.FromServiceError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, dto.TaskResponse{ // 201 + DTO
-		ID:        task.ID,        // id
-		UserID:    task.UserID,    // user
-		Title:     task.Title,     // title
-		Done:      task.Done,      // done
-		CreatedAt: task.CreatedAt, // created
-	})
+	respondTask(c, http.StatusCreated, task) // 201 + DTO
 }
 
 func (h *TaskHandler) List(c *gin.Context) { // GET /tasks
+	var filter types.TaskFilter // фильтры
+
 	// done (optional)
-	var donePtr *bool                              // nil = без фильтра
 	if doneStr := c.Query("done"); doneStr != "" { // ?done=true/false
 		v, err := strconv.ParseBool(doneStr) // парсим bool
 		if err != nil {                      // не bool
 			response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"done": "invalid"})
 			return
 		}
-		donePtr = &v // включаем фильтр
+		filter.Done = &v // включаем фильтр
+	}
+
+	// status (optional)
+	if s := c.Query("status"); s != "" { // ?status=in_progress
+		filter.Status = &s // включаем фильтр
+	}
+
+	// workspace_id (optional)
+	if s := c.Query("workspace_id"); s != "" { // ?workspace_id=...
+		v, err := strconv.ParseUint(s, 10, 64) // парсим id
+		if err != nil || v == 0 {              // не число / 0
+			response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"workspace_id": "invalid"})
+			return
+		}
+		id := uint(v)
+		filter.WorkspaceID = &id // включаем фильтр
+	}
+
+	// label (optional, repeatable)
+	filter.Labels = c.QueryArray("label")      // ?label=bug&label=frontend
+	filter.LabelMatch = c.Query("label_match") // any (по умолчанию) | all
+
+	// custom fields (optional): ?cf[customer]=acme&cf[owner]=me (нужен workspace_id)
+	filter.FieldEquals = c.QueryMap("cf")
+
+	// sort (optional): due_at | -created_at | cf.customer ...
+	filter.SortBy = c.Query("sort")
+
+	// assignee (optional): me | <user id>
+	if s := c.Query("assignee"); s != "" {
+		var id uint
+		if s == "me" { // своя очередь
+			if u, ok := middleware.CurrentUser(c); ok {
+				id = u.ID
+			}
+		} else if v, err := strconv.ParseUint(s, 10, 64); err == nil {
+			id = uint(v)
+		}
+		if id == 0 { // не me и не число
+			response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"assignee": "must be me or user id"})
+			return
+		}
+		filter.AssigneeID = &id
+	}
+
+	// watching (optional)
+	if s := c.Query("watching"); s != "" { // ?watching=true
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"watching": "invalid"})
+			return
+		}
+		if u, ok := middleware.CurrentUser(c); ok && v { // только свои подписки
+			filter.WatcherID = &u.ID
+		}
+	}
+
+	// include_archived (optional)
+	if s := c.Query("include_archived"); s != "" { // ?include_archived=true
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"include_archived": "invalid"})
+			return
+		}
+		filter.IncludeArchived = v
+	}
+
+	// aggregate (optional): конверт {items, aggregate}
+	withAggregate := false
+	if s := c.Query("aggregate"); s != "" { // ?aggregate=true
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"aggregate": "invalid"})
+			return
+		}
+		withAggregate = v
 	}
 
 	// limit/offset (optional)
@@ -81,24 +244,98 @@ func (h *TaskHandler) List(c *gin.Context) { // GET /tasks
 		offset = v // применяем
 	}
 
-	tasks, err := h.taskService.List(c.Request.Context(), donePtr, limit, offset) // вызов сервиса
-	if err != nil {                                                               // обработка ошибок
+	tasks, err := h.taskService.List(c.Request.Context(), filter, limit, offset) // вызов сервиса
+	if err != nil {                                                              // обработка ошибок
 		response.FromServiceError(c, err)
 		return
 	}
 
 	resp := make([]dto.TaskResponse, 0, len(tasks)) // DTO список
-	for _, t := range tasks {                       // маппинг в DTO
-		resp = append(resp, dto.TaskResponse{
-			ID:        t.ID,
-			UserID:    t.UserID,
-			Title:     t.Title,
-			Done:      t.Done,
-			CreatedAt: t.CreatedAt,
+	for i := range tasks {                          // маппинг в DTO
+		resp = append(resp, toTaskResponse(&tasks[i]))
+	}
+	lastModified, err := h.taskService.ListLastModified(c.Request.Context(), filter.WorkspaceID) // для If-Modified-Since
+	if err != nil {
+		response.FromServiceError(c, err)
+		return
+	}
+	if !withAggregate { // старый формат — голый массив
+		response.JSONConditional(c, http.StatusOK, resp, "", lastModified) // 200 + список (или 304)
+		return
+	}
+
+	agg, err := h.taskService.Aggregate(c.Request.Context(), filter) // суммы по всей выборке
+	if err != nil {                                                  // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+	estimates := make([]dto.EstimateSum, 0, len(agg.Estimates))
+	for _, e := range agg.Estimates {
+		estimates = append(estimates, dto.EstimateSum{Unit: e.Unit, Estimated: e.Estimated, Total: e.Total, Remaining: e.Remaining})
+	}
+
+	body := dto.TaskListResponse{Items: resp, Aggregate: dto.TaskAggregate{Count: agg.Count, Estimates: estimates}}
+	response.JSONConditional(c, http.StatusOK, body, "", lastModified) // 200 + конверт (или 304)
+}
+
+func (h *TaskHandler) Capacity(c *gin.Context) { // GET /workspaces/:id/capacity
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var week *time.Time                // nil = все открытые задачи
+	if s := c.Query("week"); s != "" { // ?week=YYYY-MM-DD (любой день недели)
+		d, err := time.Parse(time.DateOnly, s)
+		if err != nil {
+			response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"week": "must be YYYY-MM-DD"})
+			return
+		}
+		week = &d
+	}
+
+	report, err := h.taskService.Capacity(c.Request.Context(), id, week) // ёмкость против оценок
+	if err != nil {                                                      // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	resp := dto.CapacityResponse{WorkspaceID: report.WorkspaceID, Unit: report.Unit, Members: make([]dto.CapacityMember, 0, len(report.Members))}
+	if report.WeekStart != nil {
+		ws := report.WeekStart.Format(time.DateOnly)
+		resp.WeekStart = &ws
+	}
+	for _, m := range report.Members {
+		resp.Members = append(resp.Members, dto.CapacityMember{
+			User:       dto.TaskUser{ID: m.UserID, Email: m.Email},
+			Capacity:   m.Capacity,
+			Tasks:      m.Tasks,
+			Assigned:   m.Assigned,
+			Remaining:  m.Capacity - m.Assigned,
+			Overloaded: m.Capacity > 0 && m.Assigned > m.Capacity,
 		})
 	}
 
-	c.JSON(http.StatusOK, resp) // 200 + список
+	c.JSON(http.StatusOK, resp) // 200 + отчёт
+}
+
+func respondTask(c *gin.Context, status int, t *types.Task) { // карточка задачи + ETag "<version>-<hash>" / Last-Modified (GET может получить 304)
+	response.JSONConditional(c, status, toTaskResponse(t), strconv.FormatUint(uint64(t.Version), 10), t.UpdatedAt)
+}
+
+func parseIfMatch(c *gin.Context) (*uint, bool) { // If-Match: ETag задачи, сверяем только версию (нет / * — без проверки)
+	h := strings.TrimSpace(c.GetHeader("If-Match"))
+	if h == "" || h == "*" {
+		return nil, true
+	}
+	tag, quoted := strings.CutPrefix(h, `"`)
+	tag, _, _ = strings.Cut(strings.TrimSuffix(tag, `"`), "-") // хеш тела не важен: комментарии и т.п. правке не мешают
+	v, err := strconv.ParseUint(tag, 10, 32)
+	if err != nil || !quoted || !strings.HasSuffix(h, `"`) { // слабый или чужой тег не совпадёт никогда
+		response.JSONError(c, http.StatusPreconditionFailed, "precondition_failed", map[string]string{"if_match": "must be an ETag returned by the API"})
+		return nil, false
+	}
+	version := uint(v)
+	return &version, true
 }
 
 func (h *TaskHandler) GetByID(c *gin.Context) { // GET /tasks/:id
@@ -114,13 +351,36 @@ func (h *TaskHandler) GetByID(c *gin.Context) { // GET /tasks/:id
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.TaskResponse{ // 200 + DTO
-		ID:        task.ID,
-		UserID:    task.UserID,
-		Title:     task.Title,
-		Done:      task.Done,
-		CreatedAt: task.CreatedAt,
-	})
+	respondTask(c, http.StatusOK, task) // 200 + DTO
+}
+
+func toTaskPatch(req *dto.UpdateTaskRequest) types.TaskPatch { // тело PATCH -> изменения
+	patch := types.TaskPatch{
+		Title:       req.Title,
+		Description: req.Description,
+		Priority:    req.Priority,
+		Status:      req.Status,
+		Done:        req.Done,
+
+		CustomFields: req.CustomFields,
+	}
+	if req.DueAt.Set { // due_at был в теле
+		patch.DueAt = req.DueAt.Value
+		patch.ClearDueAt = req.DueAt.Value == nil // null = снять срок
+	}
+	if req.ParentID.Set { // parent_id был в теле
+		patch.ParentID = req.ParentID.Value
+		patch.ClearParentID = req.ParentID.Value == nil // null = на верхний уровень
+	}
+	if req.Estimate.Set { // estimate был в теле
+		patch.Estimate = req.Estimate.Value
+		patch.ClearEstimate = req.Estimate.Value == nil // null = снять оценку
+	}
+	if req.Recurrence.Set { // recurrence был в теле
+		patch.Recurrence = req.Recurrence.Value
+		patch.ClearRecurrence = req.Recurrence.Value == nil // null = не повторять
+	}
+	return patch
 }
 
 func (h *TaskHandler) Update(c *gin.Context) { // PATCH /tasks/:id
@@ -136,19 +396,30 @@ func (h *TaskHandler) Update(c *gin.Context) { // PATCH /tasks/:id
 		return
 	}
 
-	task, err := h.taskService.Update(c.Request.Context(), uint(id64), req.Title, req.Done) // вызов сервиса
-	if err != nil {                                                                         // обработка ошибок
+	patch := toTaskPatch(&req) // DTO -> изменения
+
+	var opts service.UpdateTaskOptions
+	if s := c.Query("force"); s != "" { // ?force=true — закрыть несмотря на блокеры
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"force": "invalid"})
+			return
+		}
+		opts.Force = v
+	}
+	ifMatch, ok := parseIfMatch(c) // If-Match: правим ту версию, что видели
+	if !ok {
+		return
+	}
+	opts.IfMatch = ifMatch
+
+	task, err := h.taskService.Update(c.Request.Context(), uint(id64), patch, opts) // вызов сервиса
+	if err != nil {                                                                 // обработка ошибок
 		response.FromServiceError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.TaskResponse{ // 200 + DTO
-		ID:        task.ID,
-		UserID:    task.UserID,
-		Title:     task.Title,
-		Done:      task.Done,
-		CreatedAt: task.CreatedAt,
-	})
+	respondTask(c, http.StatusOK, task) // 200 + DTO
 }
 
 func (h *TaskHandler) Delete(c *gin.Context) { // DELETE /tasks/:id
@@ -158,10 +429,570 @@ func (h *TaskHandler) Delete(c *gin.Context) { // DELETE /tasks/:id
 		return
 	}
 
-	if err := h.taskService.Delete(c.Request.Context(), uint(id64)); err != nil { // удалить через сервис
+	ifMatch, ok := parseIfMatch(c) // If-Match: удаляем ту версию, что видели
+	if !ok {
+		return
+	}
+	children := c.Query("children")                                                                  // reject (по умолчанию) | orphan | cascade
+	if err := h.taskService.Delete(c.Request.Context(), uint(id64), children, ifMatch); err != nil { // удалить через сервис
 		response.FromServiceError(c, err)
 		return
 	}
 
 	c.Status(http.StatusNoContent) // 204 без тела
 }
+
+func (h *TaskHandler) Bulk(c *gin.Context) { // POST /tasks/bulk
+	var req dto.BulkTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"json": "invalid"})
+		return
+	}
+
+	in := service.BulkInput{
+		IDs:        req.IDs,
+		Operation:  req.Operation,
+		Atomic:     req.Atomic == nil || *req.Atomic, // по умолчанию всё или ничего
+		Force:      req.Force,
+		Children:   req.Children,
+		LabelID:    req.LabelID,
+		AssigneeID: req.AssigneeID,
+	}
+	if req.Update != nil {
+		in.Patch = toTaskPatch(req.Update)
+	}
+	if f := req.Filter; f != nil {
+		in.Filter = &types.TaskFilter{
+			WorkspaceID:     f.WorkspaceID,
+			Status:          f.Status,
+			Done:            f.Done,
+			Labels:          f.Labels,
+			LabelMatch:      f.LabelMatch,
+			AssigneeID:      f.AssigneeID,
+			FieldEquals:     f.CustomFields,
+			IncludeArchived: f.IncludeArchived,
+		}
+	}
+
+	results, err := h.taskService.Bulk(c.Request.Context(), in) // вызов сервиса
+	if err != nil {                                             // атомарный режим: ошибка первой задачи, ничего не применено
+		response.FromServiceError(c, err)
+		return
+	}
+
+	resp := dto.BulkTaskResponse{Atomic: in.Atomic, Results: make([]dto.BulkItemResponse, 0, len(results))}
+	for _, r := range results {
+		item := dto.BulkItemResponse{ID: r.TaskID, OK: r.Err == nil}
+		if r.Err != nil {
+			resp.Failed++
+			item.Error = string(service.CodeInternal) // неизвестная ошибка — без подробностей
+			var appErr *service.AppError
+			if errors.As(r.Err, &appErr) && appErr.Code != service.CodeInternal {
+				item.Error, item.Details = string(appErr.Code), appErr.Details
+			} else {
+				c.Error(r.Err) // в лог, как FromServiceError
+			}
+		} else {
+			resp.Succeeded++
+		}
+		resp.Results = append(resp.Results, item)
+	}
+	c.JSON(http.StatusOK, resp) // 200 + отчёт (и при частичных ошибках)
+}
+
+func (h *TaskHandler) Trash(c *gin.Context) { // GET /trash
+	var workspaceID *uint                      // фильтр по пространству
+	if s := c.Query("workspace_id"); s != "" { // ?workspace_id=...
+		v, err := strconv.ParseUint(s, 10, 64) // парсим id
+		if err != nil || v == 0 {              // не число / 0
+			response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"workspace_id": "invalid"})
+			return
+		}
+		id := uint(v)
+		workspaceID = &id
+	}
+
+	limit, offset := 20, 0              // дефолты
+	if s := c.Query("limit"); s != "" { // ?limit=...
+		v, err := strconv.Atoi(s)
+		if err != nil || v <= 0 {
+			response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"limit": "invalid"})
+			return
+		}
+		limit = v
+	}
+	if s := c.Query("offset"); s != "" { // ?offset=...
+		v, err := strconv.Atoi(s)
+		if err != nil || v < 0 {
+			response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"offset": "invalid"})
+			return
+		}
+		offset = v
+	}
+
+	tasks, err := h.taskService.Trash(c.Request.Context(), workspaceID, limit, offset) // вызов сервиса
+	if err != nil {                                                                    // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	retention := h.taskService.TrashRetention()
+	resp := make([]dto.TrashItemResponse, 0, len(tasks)) // DTO список
+	for i := range tasks {                               // маппинг в DTO
+		resp = append(resp, dto.TrashItemResponse{TaskResponse: toTaskResponse(&tasks[i]), PurgeAt: tasks[i].DeletedAt.Time.Add(retention)})
+	}
+
+	c.JSON(http.StatusOK, resp) // 200 + список
+}
+
+func (h *TaskHandler) Restore(c *gin.Context) { // POST /tasks/:id/restore
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	task, err := h.taskService.Restore(c.Request.Context(), id) // вернуть из корзины
+	if err != nil {                                             // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	respondTask(c, http.StatusOK, task) // 200 + DTO
+}
+
+func (h *TaskHandler) Archive(c *gin.Context) { // POST /tasks/:id/archive
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	task, err := h.taskService.Archive(c.Request.Context(), id) // в архив
+	if err != nil {                                             // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	respondTask(c, http.StatusOK, task) // 200 + DTO
+}
+
+func (h *TaskHandler) Unarchive(c *gin.Context) { // POST /tasks/:id/unarchive
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	task, err := h.taskService.Unarchive(c.Request.Context(), id) // из архива
+	if err != nil {                                               // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	respondTask(c, http.StatusOK, task) // 200 + DTO
+}
+
+func (h *TaskHandler) ArchiveDone(c *gin.Context) { // POST /tasks/archive-done
+	var req dto.ArchiveDoneRequest                                            // тело (опционально)
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) { // пустое тело = дефолты
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"json": "invalid"})
+		return
+	}
+	days := 30 // дефолт
+	if req.OlderThanDays != nil {
+		days = *req.OlderThanDays
+	}
+
+	n, err := h.taskService.ArchiveDone(c.Request.Context(), req.WorkspaceID, days) // вызов сервиса
+	if err != nil {                                                                 // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ArchiveDoneResponse{Archived: n}) // 200 + сколько
+}
+
+func toRevisionResponse(r *types.TaskRevision) dto.RevisionResponse { // модель -> DTO
+	var user *dto.TaskUser
+	if r.User != nil { // автор ещё существует
+		user = &dto.TaskUser{ID: r.User.ID, Email: r.User.Email}
+	}
+	changes := make([]dto.FieldChangeResponse, 0, len(r.Changes)) // всегда массив, не null
+	for _, ch := range r.Changes {
+		changes = append(changes, dto.FieldChangeResponse{Field: ch.Field, Before: ch.Before, After: ch.After})
+	}
+	return dto.RevisionResponse{ID: r.ID, TaskID: r.TaskID, Action: r.Action, User: user, RevertedFrom: r.RevertedFrom, Changes: changes, CreatedAt: r.CreatedAt}
+}
+
+func (h *TaskHandler) History(c *gin.Context) { // GET /tasks/:id/history
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	limit, offset := 50, 0              // дефолты
+	if s := c.Query("limit"); s != "" { // ?limit=...
+		v, err := strconv.Atoi(s)
+		if err != nil || v <= 0 {
+			response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"limit": "invalid"})
+			return
+		}
+		limit = v
+	}
+	if s := c.Query("offset"); s != "" { // ?offset=...
+		v, err := strconv.Atoi(s)
+		if err != nil || v < 0 {
+			response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"offset": "invalid"})
+			return
+		}
+		offset = v
+	}
+
+	revisions, err := h.taskService.History(c.Request.Context(), id, limit, offset) // вызов сервиса
+	if err != nil {                                                                 // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	resp := make([]dto.RevisionResponse, 0, len(revisions)) // DTO список
+	for i := range revisions {                              // маппинг в DTO
+		resp = append(resp, toRevisionResponse(&revisions[i]))
+	}
+
+	c.JSON(http.StatusOK, resp) // 200 + история (свежие первыми)
+}
+
+func (h *TaskHandler) Revert(c *gin.Context) { // POST /tasks/:id/history/:revisionId/revert
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	revisionID, ok := parseIDParam(c, "revisionId")
+	if !ok {
+		return
+	}
+
+	var opts service.UpdateTaskOptions
+	if s := c.Query("force"); s != "" { // ?force=true — вернуть закрытый статус несмотря на блокеры
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"force": "invalid"})
+			return
+		}
+		opts.Force = v
+	}
+	if opts.IfMatch, ok = parseIfMatch(c); !ok {
+		return
+	}
+
+	task, err := h.taskService.Revert(c.Request.Context(), id, revisionID, opts) // откат
+	if err != nil {                                                              // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	respondTask(c, http.StatusOK, task) // 200 + DTO
+}
+
+func (h *TaskHandler) Children(c *gin.Context) { // GET /tasks/:id/children
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	tasks, err := h.taskService.Children(c.Request.Context(), id) // вызов сервиса
+	if err != nil {                                               // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	resp := make([]dto.TaskResponse, 0, len(tasks)) // DTO список
+	for i := range tasks {                          // маппинг в DTO
+		resp = append(resp, toTaskResponse(&tasks[i]))
+	}
+
+	c.JSON(http.StatusOK, resp) // 200 + список
+}
+
+func (h *TaskHandler) Subtree(c *gin.Context) { // GET /tasks/:id/subtree
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	root, tasks, err := h.taskService.Subtree(c.Request.Context(), id) // корень + потомки
+	if err != nil {                                                    // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	byParent := make(map[uint][]*types.Task, len(tasks)) // parent_id -> подзадачи
+	for i := range tasks {
+		if p := tasks[i].ParentID; p != nil {
+			byParent[*p] = append(byParent[*p], &tasks[i])
+		}
+	}
+
+	var build func(t *types.Task) dto.TaskTreeResponse // собрать дерево рекурсивно
+	build = func(t *types.Task) dto.TaskTreeResponse {
+		node := dto.TaskTreeResponse{TaskResponse: toTaskResponse(t), Children: []dto.TaskTreeResponse{}}
+		for _, child := range byParent[t.ID] {
+			node.Children = append(node.Children, build(child))
+		}
+		return node
+	}
+
+	c.JSON(http.StatusOK, build(root)) // 200 + дерево
+}
+
+func (h *TaskHandler) Occurrences(c *gin.Context) { // GET /tasks/:id/occurrences
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	limit := 0 // 0 = дефолт сервиса
+	if s := c.Query("limit"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil || v <= 0 {
+			response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"limit": "must be positive integer"})
+			return
+		}
+		limit = v
+	}
+
+	task, dates, err := h.taskService.Occurrences(c.Request.Context(), id, limit) // правило + ближайшие сроки
+	if err != nil {                                                               // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.OccurrencesResponse{Recurrence: task.Recurrence, Occurrences: dates}) // 200 + превью
+}
+
+func (h *TaskHandler) AddLabel(c *gin.Context) { // PUT /tasks/:id/labels/:labelId
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	labelID, ok := parseIDParam(c, "labelId")
+	if !ok {
+		return
+	}
+
+	task, err := h.taskService.AddLabel(c.Request.Context(), id, labelID) // повесить метку
+	if err != nil {                                                       // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	respondTask(c, http.StatusOK, task) // 200 + DTO
+}
+
+func (h *TaskHandler) RemoveLabel(c *gin.Context) { // DELETE /tasks/:id/labels/:labelId
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	labelID, ok := parseIDParam(c, "labelId")
+	if !ok {
+		return
+	}
+
+	task, err := h.taskService.RemoveLabel(c.Request.Context(), id, labelID) // снять метку
+	if err != nil {                                                          // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	respondTask(c, http.StatusOK, task) // 200 + DTO
+}
+
+func (h *TaskHandler) AddDependency(c *gin.Context) { // PUT /tasks/:id/blocked-by/:blockerId
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	blockerID, ok := parseIDParam(c, "blockerId")
+	if !ok {
+		return
+	}
+
+	task, err := h.taskService.AddDependency(c.Request.Context(), id, blockerID) // добавить ребро
+	if err != nil {                                                              // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	respondTask(c, http.StatusOK, task) // 200 + DTO
+}
+
+func (h *TaskHandler) RemoveDependency(c *gin.Context) { // DELETE /tasks/:id/blocked-by/:blockerId
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	blockerID, ok := parseIDParam(c, "blockerId")
+	if !ok {
+		return
+	}
+
+	task, err := h.taskService.RemoveDependency(c.Request.Context(), id, blockerID) // убрать ребро
+	if err != nil {                                                                 // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	respondTask(c, http.StatusOK, task) // 200 + DTO
+}
+
+func (h *TaskHandler) Assign(c *gin.Context) { // PUT /tasks/:id/assignees/:userId
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := parseIDParam(c, "userId")
+	if !ok {
+		return
+	}
+
+	task, err := h.taskService.Assign(c.Request.Context(), id, userID) // назначить
+	if err != nil {                                                    // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	respondTask(c, http.StatusOK, task) // 200 + DTO
+}
+
+func (h *TaskHandler) Unassign(c *gin.Context) { // DELETE /tasks/:id/assignees/:userId
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := parseIDParam(c, "userId")
+	if !ok {
+		return
+	}
+
+	task, err := h.taskService.Unassign(c.Request.Context(), id, userID) // снять
+	if err != nil {                                                      // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	respondTask(c, http.StatusOK, task) // 200 + DTO
+}
+
+func (h *TaskHandler) Watch(c *gin.Context) { // PUT /tasks/:id/watch
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	task, err := h.taskService.Watch(c.Request.Context(), id) // подписаться
+	if err != nil {                                           // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	respondTask(c, http.StatusOK, task) // 200 + DTO
+}
+
+func (h *TaskHandler) Unwatch(c *gin.Context) { // DELETE /tasks/:id/watch
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	task, err := h.taskService.Unwatch(c.Request.Context(), id) // отписаться
+	if err != nil {                                             // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	respondTask(c, http.StatusOK, task) // 200 + DTO
+}
+
+func (h *TaskHandler) AddChecklistItem(c *gin.Context) { // POST /tasks/:id/checklist
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.CreateChecklistItemRequest         // тело запроса
+	if err := c.ShouldBindJSON(&req); err != nil { // распарсить JSON
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"json": "invalid"})
+		return
+	}
+
+	task, err := h.taskService.AddChecklistItem(c.Request.Context(), id, req.Title) // добавить пункт
+	if err != nil {                                                                 // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	respondTask(c, http.StatusCreated, task) // 201 + карточка задачи
+}
+
+func (h *TaskHandler) UpdateChecklistItem(c *gin.Context) { // PATCH /tasks/:id/checklist/:itemId
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	itemID, ok := parseIDParam(c, "itemId")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateChecklistItemRequest         // тело PATCH
+	if err := c.ShouldBindJSON(&req); err != nil { // парсим JSON
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"json": "invalid"})
+		return
+	}
+
+	task, err := h.taskService.UpdateChecklistItem(c.Request.Context(), id, itemID, req.Title, req.Done) // переименовать / отметить
+	if err != nil {                                                                                      // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	respondTask(c, http.StatusOK, task) // 200 + карточка задачи
+}
+
+func (h *TaskHandler) ReorderChecklist(c *gin.Context) { // PUT /tasks/:id/checklist/order
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.ReorderChecklistRequest            // тело запроса
+	if err := c.ShouldBindJSON(&req); err != nil { // парсим JSON
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"json": "invalid"})
+		return
+	}
+
+	task, err := h.taskService.ReorderChecklist(c.Request.Context(), id, req.ItemIDs) // новый порядок
+	if err != nil {                                                                   // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	respondTask(c, http.StatusOK, task) // 200 + карточка задачи
+}
+
+func (h *TaskHandler) DeleteChecklistItem(c *gin.Context) { // DELETE /tasks/:id/checklist/:itemId
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	itemID, ok := parseIDParam(c, "itemId")
+	if !ok {
+		return
+	}
+
+	task, err := h.taskService.DeleteChecklistItem(c.Request.Context(), id, itemID) // удалить пункт
+	if err != nil {                                                                 // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	respondTask(c, http.StatusOK, task) // 200 + карточка задачи
+}
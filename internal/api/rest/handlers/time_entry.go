@@ -0,0 +1,221 @@
+package handlers // HTTP-хендлеры
+
+import (
+	"errors"   // errors.Is
+	"io"       // пустое тело
+	"net/http" // HTTP статусы
+	"strconv"  // parse workspace_id/user_id
+	"time"     // from/to, длительность
+
+	"github.com/gin-gonic/gin" // Gin
+
+	"task-tracker/internal/api/rest/dto" // DTO
+	"task-tracker/internal/api/rest/response"
+	"task-tracker/internal/domain/middleware" // текущий пользователь
+	"task-tracker/internal/domain/service"    // сервис
+	"task-tracker/internal/domain/types"      // модели
+)
+
+type TimeEntryHandler struct { // хендлер учёта времени
+	timeService *service.TimeEntryService // зависимость
+}
+
+func NewTimeEntryHandler(timeService *service.TimeEntryService) *TimeEntryHandler { // конструктор
+	return &TimeEntryHandler{timeService: timeService} // сохранить сервис
+}
+
+func toTimeEntryResponse(e *types.TimeEntry) dto.TimeEntryResponse { // модель -> DTO
+	resp := dto.TimeEntryResponse{
+		ID:              e.ID,
+		TaskID:          e.TaskID,
+		User:            dto.TaskUser{ID: e.UserID},
+		StartedAt:       e.StartedAt,
+		EndedAt:         e.EndedAt,
+		DurationSeconds: e.Seconds,
+		Running:         e.Running(),
+		Note:            e.Note,
+		CreatedAt:       e.CreatedAt,
+	}
+	if e.Running() { // сколько уже натикало
+		resp.DurationSeconds = int64(time.Since(e.StartedAt) / time.Second)
+	}
+	if e.User != nil { // подгружен
+		resp.User.Email = e.User.Email
+	}
+	return resp
+}
+
+func (h *TimeEntryHandler) StartTimer(c *gin.Context) { // POST /tasks/:id/timer/start
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.StartTimerRequest                                             // тело необязательно
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) { // распарсить JSON
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"json": "invalid"})
+		return
+	}
+
+	entry, err := h.timeService.StartTimer(c.Request.Context(), taskID, req.Note) // запустить
+	if err != nil {                                                               // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toTimeEntryResponse(entry)) // 201 + DTO
+}
+
+func (h *TimeEntryHandler) StopTimer(c *gin.Context) { // POST /tasks/:id/timer/stop
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	entry, err := h.timeService.StopTimer(c.Request.Context(), taskID) // остановить
+	if err != nil {                                                    // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toTimeEntryResponse(entry)) // 200 + DTO
+}
+
+func (h *TimeEntryHandler) Create(c *gin.Context) { // POST /tasks/:id/time-entries
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.CreateTimeEntryRequest             // тело запроса
+	if err := c.ShouldBindJSON(&req); err != nil { // распарсить JSON
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"json": "invalid"})
+		return
+	}
+
+	entry, err := h.timeService.AddEntry(c.Request.Context(), taskID, service.ManualTimeEntryInput{ // записать вручную
+		StartedAt: req.StartedAt,
+		Duration:  time.Duration(req.DurationSeconds) * time.Second,
+		Note:      req.Note,
+	})
+	if err != nil { // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toTimeEntryResponse(entry)) // 201 + DTO
+}
+
+func (h *TimeEntryHandler) List(c *gin.Context) { // GET /tasks/:id/time-entries
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	entries, err := h.timeService.List(c.Request.Context(), taskID) // записи задачи
+	if err != nil {                                                 // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	resp := make([]dto.TimeEntryResponse, 0, len(entries)) // DTO список
+	for i := range entries {
+		resp = append(resp, toTimeEntryResponse(&entries[i]))
+	}
+
+	c.JSON(http.StatusOK, resp) // 200 + список
+}
+
+func (h *TimeEntryHandler) Delete(c *gin.Context) { // DELETE /tasks/:id/time-entries/:entryId
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	entryID, ok := parseIDParam(c, "entryId")
+	if !ok {
+		return
+	}
+
+	if err := h.timeService.Delete(c.Request.Context(), taskID, entryID); err != nil { // удалить (только автор)
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent) // 204 без тела
+}
+
+func (h *TimeEntryHandler) Report(c *gin.Context) { // GET /reports/time
+	var filter types.TimeReportFilter // параметры отчёта
+
+	// from/to (optional): YYYY-MM-DD, to включительно
+	if s := c.Query("from"); s != "" {
+		d, err := time.Parse(time.DateOnly, s)
+		if err != nil {
+			response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"from": "must be YYYY-MM-DD"})
+			return
+		}
+		filter.From = d
+	}
+	if s := c.Query("to"); s != "" {
+		d, err := time.Parse(time.DateOnly, s)
+		if err != nil {
+			response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"to": "must be YYYY-MM-DD"})
+			return
+		}
+		filter.To = d.AddDate(0, 0, 1) // до конца дня
+	}
+
+	// workspace_id (optional)
+	if s := c.Query("workspace_id"); s != "" {
+		v, err := strconv.ParseUint(s, 10, 64)
+		if err != nil || v == 0 {
+			response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"workspace_id": "invalid"})
+			return
+		}
+		id := uint(v)
+		filter.WorkspaceID = &id
+	}
+
+	// user (optional): me | <user id>
+	if s := c.Query("user"); s != "" {
+		var id uint
+		if s == "me" { // только своё время
+			if u, ok := middleware.CurrentUser(c); ok {
+				id = u.ID
+			}
+		} else if v, err := strconv.ParseUint(s, 10, 64); err == nil {
+			id = uint(v)
+		}
+		if id == 0 { // не me и не число
+			response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"user": "must be me or user id"})
+			return
+		}
+		filter.UserID = &id
+	}
+
+	report, err := h.timeService.Report(c.Request.Context(), filter) // агрегаты
+	if err != nil {                                                  // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	resp := dto.TimeReportResponse{
+		From:         report.From,
+		To:           report.To,
+		TotalSeconds: report.TotalSeconds,
+		ByUser:       make([]dto.TimeByUserRow, 0, len(report.ByUser)),
+		ByLabel:      make([]dto.TimeByLabelRow, 0, len(report.ByLabel)),
+		ByDay:        make([]dto.TimeByDayRow, 0, len(report.ByDay)),
+	}
+	for _, row := range report.ByUser {
+		resp.ByUser = append(resp.ByUser, dto.TimeByUserRow{User: dto.TaskUser{ID: row.UserID, Email: row.Email}, Seconds: row.Seconds})
+	}
+	for _, row := range report.ByLabel {
+		resp.ByLabel = append(resp.ByLabel, dto.TimeByLabelRow{LabelID: row.LabelID, Name: row.Name, Seconds: row.Seconds})
+	}
+	for _, row := range report.ByDay {
+		resp.ByDay = append(resp.ByDay, dto.TimeByDayRow{Day: row.Day.Format(time.DateOnly), Seconds: row.Seconds})
+	}
+
+	c.JSON(http.StatusOK, resp) // 200 + отчёт
+}
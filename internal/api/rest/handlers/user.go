@@ -0,0 +1,135 @@
+package handlers // HTTP-хендлеры
+
+import (
+	"net/http" // HTTP статусы
+	"strconv"  // parse id
+
+	"github.com/gin-gonic/gin" // Gin
+
+	"task-tracker/internal/api/rest/dto" // DTO
+	"task-tracker/internal/api/rest/response"
+	"task-tracker/internal/domain/service" // сервис
+	"task-tracker/internal/domain/types"   // модели
+)
+
+type UserHandler struct { // хендлер пользователей
+	userService *service.UserService // зависимость
+}
+
+func NewUserHandler(userService *service.UserService) *UserHandler { // конструктор
+	return &UserHandler{userService: userService} // сохранить сервис
+}
+
+func toUserResponse(u *types.User) dto.UserResponse { // модель -> DTO
+	return dto.UserResponse{
+		ID:        u.ID,
+		Email:     u.Email,
+		Role:      u.Role,
+		CreatedAt: u.CreatedAt,
+	}
+}
+
+func (h *UserHandler) Create(c *gin.Context) { // POST /users
+	var req dto.CreateUserRequest                  // тело запроса
+	if err := c.ShouldBindJSON(&req); err != nil { // распарсить JSON
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"json": "invalid"})
+		return
+	}
+
+	user, err := h.userService.Create(c.Request.Context(), req.Email) // создать пользователя
+	if err != nil {                                                   // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toUserResponse(user)) // 201 + DTO
+}
+
+func (h *UserHandler) List(c *gin.Context) { // GET /users
+	limit := 20 // дефолт
+	offset := 0 // дефолт
+
+	if s := c.Query("limit"); s != "" { // ?limit=...
+		v, err := strconv.Atoi(s) // парсим int
+		if err != nil || v <= 0 { // не число / <=0
+			response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"limit": "invalid"})
+			return
+		}
+		limit = v // применяем
+	}
+
+	if s := c.Query("offset"); s != "" { // ?offset=...
+		v, err := strconv.Atoi(s) // парсим int
+		if err != nil || v < 0 {  // не число / <0
+			response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"offset": "invalid"})
+			return
+		}
+		offset = v // применяем
+	}
+
+	users, err := h.userService.List(c.Request.Context(), limit, offset) // вызов сервиса
+	if err != nil {                                                      // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	resp := make([]dto.UserResponse, 0, len(users)) // DTO список
+	for i := range users {                          // маппинг в DTO
+		resp = append(resp, toUserResponse(&users[i]))
+	}
+
+	c.JSON(http.StatusOK, resp) // 200 + список
+}
+
+func (h *UserHandler) GetByID(c *gin.Context) { // GET /users/:id
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id64 == 0 { // не число / 0
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"id": "must be positive integer"})
+		return
+	}
+
+	user, err := h.userService.GetByID(c.Request.Context(), uint(id64)) // получить пользователя
+	if err != nil {                                                     // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toUserResponse(user)) // 200 + DTO
+}
+
+func (h *UserHandler) Update(c *gin.Context) { // PATCH /users/:id
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id64 == 0 { // не число / 0
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"id": "invalid"})
+		return
+	}
+
+	var req dto.UpdateUserRequest                  // тело PATCH
+	if err := c.ShouldBindJSON(&req); err != nil { // парсим JSON
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"json": "invalid"})
+		return
+	}
+
+	user, err := h.userService.Update(c.Request.Context(), uint(id64), req.Email, req.Role) // вызов сервиса
+	if err != nil {                                                                         // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toUserResponse(user)) // 200 + DTO
+}
+
+func (h *UserHandler) Delete(c *gin.Context) { // DELETE /users/:id
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id64 == 0 { // не число / 0
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"id": "invalid"})
+		return
+	}
+
+	if err := h.userService.Delete(c.Request.Context(), uint(id64)); err != nil { // удалить через сервис
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent) // 204 без тела
+}
@@ -3,7 +3,7 @@ package handlers // HTTP-хендлеры
 import (
 	"net/http" // HTTP статусы
 
-	"github.com/gin-gonic/gin"            // Gin контекст
+	"github.com/gin-gonic/gin"             // Gin контекст
 	"task-tracker/internal/domain/service" // сервисный слой
 )
 
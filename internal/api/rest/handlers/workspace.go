@@ -0,0 +1,347 @@
+package handlers // HTTP-хендлеры
+
+import (
+	"net/http" // HTTP статусы
+	"strconv"  // parse id
+
+	"github.com/gin-gonic/gin" // Gin
+
+	"task-tracker/internal/api/rest/dto" // DTO
+	"task-tracker/internal/api/rest/response"
+	"task-tracker/internal/domain/service" // сервис
+	"task-tracker/internal/domain/types"   // модели
+)
+
+type WorkspaceHandler struct { // хендлер рабочих пространств
+	workspaceService *service.WorkspaceService // зависимость
+}
+
+func NewWorkspaceHandler(workspaceService *service.WorkspaceService) *WorkspaceHandler { // конструктор
+	return &WorkspaceHandler{workspaceService: workspaceService} // сохранить сервис
+}
+
+func toWorkspaceResponse(ws *types.Workspace) dto.WorkspaceResponse { // модель -> DTO
+	return dto.WorkspaceResponse{ID: ws.ID, Name: ws.Name, EstimateUnit: ws.EstimateUnit, WeeklyCapacity: ws.WeeklyCapacity, CreatedAt: ws.CreatedAt}
+}
+
+func toInvitationResponse(inv *types.WorkspaceInvitation) dto.InvitationResponse { // модель -> DTO
+	return dto.InvitationResponse{
+		ID:          inv.ID,
+		WorkspaceID: inv.WorkspaceID,
+		Email:       inv.Email,
+		Role:        inv.Role,
+		ExpiresAt:   inv.ExpiresAt,
+		CreatedAt:   inv.CreatedAt,
+	}
+}
+
+func parseIDParam(c *gin.Context, name string) (uint, bool) { // положительный id из пути
+	id64, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil || id64 == 0 { // не число / 0
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{name: "must be positive integer"})
+		return 0, false
+	}
+	return uint(id64), true
+}
+
+func (h *WorkspaceHandler) Create(c *gin.Context) { // POST /workspaces
+	var req dto.WorkspaceRequest                   // тело запроса
+	if err := c.ShouldBindJSON(&req); err != nil { // распарсить JSON
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"json": "invalid"})
+		return
+	}
+
+	ws, err := h.workspaceService.Create(c.Request.Context(), req.Name) // создать
+	if err != nil {                                                     // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toWorkspaceResponse(ws)) // 201 + DTO
+}
+
+func (h *WorkspaceHandler) List(c *gin.Context) { // GET /workspaces
+	list, err := h.workspaceService.List(c.Request.Context()) // вызов сервиса
+	if err != nil {                                           // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	resp := make([]dto.WorkspaceResponse, 0, len(list)) // DTO список
+	for i := range list {                               // маппинг в DTO
+		resp = append(resp, toWorkspaceResponse(&list[i]))
+	}
+
+	c.JSON(http.StatusOK, resp) // 200 + список
+}
+
+func (h *WorkspaceHandler) GetByID(c *gin.Context) { // GET /workspaces/:id
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	ws, err := h.workspaceService.GetByID(c.Request.Context(), id) // получить
+	if err != nil {                                                // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toWorkspaceResponse(ws)) // 200 + DTO
+}
+
+func (h *WorkspaceHandler) Update(c *gin.Context) { // PATCH /workspaces/:id
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateWorkspaceRequest             // тело PATCH
+	if err := c.ShouldBindJSON(&req); err != nil { // парсим JSON
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"json": "invalid"})
+		return
+	}
+
+	ws, err := h.workspaceService.Update(c.Request.Context(), id, types.WorkspacePatch{ // название и настройки
+		Name:           req.Name,
+		EstimateUnit:   req.EstimateUnit,
+		WeeklyCapacity: req.WeeklyCapacity,
+	})
+	if err != nil { // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toWorkspaceResponse(ws)) // 200 + DTO
+}
+
+func (h *WorkspaceHandler) Delete(c *gin.Context) { // DELETE /workspaces/:id
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.workspaceService.Delete(c.Request.Context(), id); err != nil { // удалить через сервис
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent) // 204 без тела
+}
+
+func (h *WorkspaceHandler) Members(c *gin.Context) { // GET /workspaces/:id/members
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	members, err := h.workspaceService.Members(c.Request.Context(), id) // вызов сервиса
+	if err != nil {                                                     // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	resp := make([]dto.WorkspaceMemberResponse, 0, len(members)) // DTO список
+	for _, m := range members {                                  // маппинг в DTO
+		resp = append(resp, dto.WorkspaceMemberResponse{
+			UserID:         m.UserID,
+			Email:          m.User.Email,
+			Role:           m.Role,
+			WeeklyCapacity: m.WeeklyCapacity,
+			JoinedAt:       m.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, resp) // 200 + список
+}
+
+func (h *WorkspaceHandler) UpdateMember(c *gin.Context) { // PATCH /workspaces/:id/members/:userId
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := parseIDParam(c, "userId")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateMemberRequest                // тело PATCH
+	if err := c.ShouldBindJSON(&req); err != nil { // парсим JSON
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"json": "invalid"})
+		return
+	}
+
+	if req.Role == "" && !req.WeeklyCapacity.Set { // нечего менять
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"body": "nothing to update"})
+		return
+	}
+	if req.Role != "" {
+		if err := h.workspaceService.UpdateMemberRole(c.Request.Context(), id, userID, req.Role); err != nil { // сменить роль
+			response.FromServiceError(c, err)
+			return
+		}
+	}
+	if req.WeeklyCapacity.Set {
+		if err := h.workspaceService.SetMemberCapacity(c.Request.Context(), id, userID, req.WeeklyCapacity.Value); err != nil { // своя ёмкость
+			response.FromServiceError(c, err)
+			return
+		}
+	}
+
+	c.Status(http.StatusNoContent) // 204 без тела
+}
+
+func (h *WorkspaceHandler) RemoveMember(c *gin.Context) { // DELETE /workspaces/:id/members/:userId
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := parseIDParam(c, "userId")
+	if !ok {
+		return
+	}
+
+	if err := h.workspaceService.RemoveMember(c.Request.Context(), id, userID); err != nil { // исключить
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent) // 204 без тела
+}
+
+func (h *WorkspaceHandler) Invite(c *gin.Context) { // POST /workspaces/:id/invitations
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.InviteRequest                      // тело запроса
+	if err := c.ShouldBindJSON(&req); err != nil { // распарсить JSON
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"json": "invalid"})
+		return
+	}
+
+	inv, token, err := h.workspaceService.Invite(c.Request.Context(), id, req.Email, req.Role) // пригласить
+	if err != nil {                                                                            // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreatedInvitationResponse{ // 201 + токен (единственный раз)
+		InvitationResponse: toInvitationResponse(inv),
+		Token:              token,
+	})
+}
+
+func (h *WorkspaceHandler) Invitations(c *gin.Context) { // GET /workspaces/:id/invitations
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	list, err := h.workspaceService.Invitations(c.Request.Context(), id) // вызов сервиса
+	if err != nil {                                                      // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	resp := make([]dto.InvitationResponse, 0, len(list)) // DTO список
+	for i := range list {                                // маппинг в DTO
+		resp = append(resp, toInvitationResponse(&list[i]))
+	}
+
+	c.JSON(http.StatusOK, resp) // 200 + список
+}
+
+func (h *WorkspaceHandler) RevokeInvitation(c *gin.Context) { // DELETE /workspaces/:id/invitations/:invitationId
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	invID, ok := parseIDParam(c, "invitationId")
+	if !ok {
+		return
+	}
+
+	if err := h.workspaceService.RevokeInvitation(c.Request.Context(), id, invID); err != nil { // отозвать
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent) // 204 без тела
+}
+
+func (h *WorkspaceHandler) AcceptInvitation(c *gin.Context) { // POST /invitations/accept
+	var req dto.AcceptInvitationRequest                               // тело запроса
+	if err := c.ShouldBindJSON(&req); err != nil || req.Token == "" { // распарсить JSON
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"token": "required"})
+		return
+	}
+
+	ws, err := h.workspaceService.AcceptInvitation(c.Request.Context(), req.Token) // принять
+	if err != nil {                                                                // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toWorkspaceResponse(ws)) // 200 + пространство
+}
+
+func toWorkflowDTO(wf *types.Workflow) dto.WorkflowDTO { // модель -> DTO
+	resp := dto.WorkflowDTO{
+		Initial:     wf.Initial,
+		Statuses:    make([]dto.WorkflowStatusDTO, 0, len(wf.Statuses)),
+		Transitions: make([]dto.WorkflowTransitionDTO, 0, len(wf.Transitions)),
+	}
+	for _, st := range wf.Statuses {
+		resp.Statuses = append(resp.Statuses, dto.WorkflowStatusDTO{Key: st.Key, Name: st.Name, Done: st.Done})
+	}
+	for _, t := range wf.Transitions {
+		resp.Transitions = append(resp.Transitions, dto.WorkflowTransitionDTO{From: t.From, To: t.To})
+	}
+	return resp
+}
+
+func (h *WorkspaceHandler) GetWorkflow(c *gin.Context) { // GET /workspaces/:id/workflow
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	wf, err := h.workspaceService.Workflow(c.Request.Context(), id) // вызов сервиса
+	if err != nil {                                                 // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toWorkflowDTO(wf)) // 200 + DTO
+}
+
+func (h *WorkspaceHandler) PutWorkflow(c *gin.Context) { // PUT /workspaces/:id/workflow
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.WorkflowDTO                        // тело PUT
+	if err := c.ShouldBindJSON(&req); err != nil { // парсим JSON
+		response.JSONError(c, http.StatusBadRequest, "validation_error", map[string]string{"json": "invalid"})
+		return
+	}
+
+	wf := &types.Workflow{Initial: req.Initial} // DTO -> модель
+	for _, st := range req.Statuses {
+		wf.Statuses = append(wf.Statuses, types.WorkflowStatus{Key: st.Key, Name: st.Name, Done: st.Done})
+	}
+	for _, t := range req.Transitions {
+		wf.Transitions = append(wf.Transitions, types.WorkflowTransition{From: t.From, To: t.To})
+	}
+
+	wf, err := h.workspaceService.SetWorkflow(c.Request.Context(), id, wf) // заменить процесс
+	if err != nil {                                                        // обработка ошибок
+		response.FromServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toWorkflowDTO(wf)) // 200 + DTO
+}
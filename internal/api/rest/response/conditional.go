@@ -0,0 +1,67 @@
+package response // общий слой ответов
+
+import (
+	"crypto/sha256" // ETag из тела
+	"encoding/hex"  // хеш -> строка
+	"encoding/json" // тело считаем заранее
+	"net/http"      // HTTP статусы, TimeFormat
+	"strings"       // разбор If-None-Match
+	"time"          // Last-Modified
+
+	"task-tracker/internal/domain/service" // код ошибки
+
+	"github.com/gin-gonic/gin" // Gin
+)
+
+func JSONConditional(c *gin.Context, status int, body any, version string, lastModified time.Time) { // JSON + ETag/Last-Modified; GET с теми же валидаторами -> 304
+	// хеш покрывает всё тело; lastModified верен, пока всё, что меняет производные поля (комментарии, учёт времени,
+	// прогресс, метки), сдвигает updated_at задачи (TaskRepository.Touch) — иначе его не передают
+	data, err := json.Marshal(body) // тело нужно до ответа — из него ETag
+	if err != nil {
+		c.Error(err)
+		JSONError(c, http.StatusInternalServerError, string(service.CodeInternal), nil) // 500
+		return
+	}
+	sum := sha256.Sum256(data)
+	tag := hex.EncodeToString(sum[:8]) // 64 бит хватает, чтобы различать версии одного ресурса
+	if version != "" {                 // "<версия>-<хеш>": версия нужна для If-Match
+		tag = version + "-" + tag
+	}
+	etag := `"` + tag + `"`
+
+	h := c.Writer.Header()
+	h.Set("ETag", etag)
+	h.Set("Cache-Control", "private, no-cache") // кешировать можно, но перепроверять каждый раз
+	if !lastModified.IsZero() {
+		h.Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+		if notModified(c.Request, etag, lastModified) {
+			c.Status(http.StatusNotModified) // 304 без тела
+			return
+		}
+	}
+	c.Data(status, "application/json; charset=utf-8", data)
+}
+
+func notModified(r *http.Request, etag string, lastModified time.Time) bool { // RFC 9110, 13.2.2: If-None-Match важнее If-Modified-Since
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, t := range strings.Split(inm, ",") {
+			t = strings.TrimSpace(t)
+			if t == "*" || strings.TrimPrefix(t, "W/") == etag { // слабое сравнение
+				return true
+			}
+		}
+		return false
+	}
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" || lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(ims)
+	if err != nil { // кривую дату игнорируем
+		return false
+	}
+	return !lastModified.Truncate(time.Second).After(since) // в заголовке точность — секунды
+}
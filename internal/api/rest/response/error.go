@@ -31,10 +31,34 @@ func FromServiceError(c *gin.Context, err error) { // маппинг ошибо
 			c.Error(err)                                                             // сохранить в контексте
 			JSONError(c, http.StatusBadRequest, string(appErr.Code), appErr.Details) // 400
 			return
+		case service.CodeUnauthorized: // unauthorized
+			c.Error(err)
+			JSONError(c, http.StatusUnauthorized, string(appErr.Code), appErr.Details) // 401
+			return
+		case service.CodeForbidden: // forbidden
+			c.Error(err)
+			JSONError(c, http.StatusForbidden, string(appErr.Code), appErr.Details) // 403
+			return
 		case service.CodeNotFound: // not_found
 			c.Error(err)
 			JSONError(c, http.StatusNotFound, string(appErr.Code), appErr.Details) // 404
 			return
+		case service.CodeInvalidTransition: // invalid_transition
+			c.Error(err)
+			JSONError(c, http.StatusUnprocessableEntity, string(appErr.Code), appErr.Details) // 422
+			return
+		case service.CodeTooLarge: // too_large
+			c.Error(err)
+			JSONError(c, http.StatusRequestEntityTooLarge, string(appErr.Code), appErr.Details) // 413
+			return
+		case service.CodePrecondition: // precondition_failed
+			c.Error(err)
+			JSONError(c, http.StatusPreconditionFailed, string(appErr.Code), appErr.Details) // 412
+			return
+		case service.CodeConflict: // conflict
+			c.Error(err)
+			JSONError(c, http.StatusConflict, string(appErr.Code), appErr.Details) // 409
+			return
 		default: // всё остальное
 			c.Error(err)
 			JSONError(c, http.StatusInternalServerError, string(service.CodeInternal), nil) // 500
@@ -3,12 +3,36 @@ package config // пакет конфигурации
 import (
 	"fmt"     // ошибки/формат
 	"os"      // env vars
+	"strconv" // размеры
 	"strings" // TrimSpace
+	"time"    // длительности
 )
 
 type Config struct { // конфиг приложения
-	Port        string // порт сервера
-	DatabaseURL string // DSN БД
+	Port            string        // порт сервера
+	DatabaseURL     string        // DSN БД
+	JWTSecret       string        // ключ подписи access-токенов
+	AccessTokenTTL  time.Duration // срок жизни access-токена
+	RefreshTokenTTL time.Duration // срок жизни refresh-токена
+	AdminEmails     []string      // email пользователей с ролью admin
+	InviteTTL       time.Duration // срок жизни приглашения в пространство
+
+	BlobBackend        string // local | s3 — где хранить вложения
+	BlobDir            string // папка для local
+	S3Endpoint         string // http(s)://host[:port] S3-совместимого хранилища
+	S3Region           string // регион подписи
+	S3Bucket           string // бакет
+	S3AccessKey        string // ключ доступа
+	S3SecretKey        string // секрет
+	AttachmentMaxBytes int64  // предельный размер вложения
+
+	ChecklistAutoComplete bool // отмеченный последний пункт чек-листа закрывает задачу
+
+	TrashRetention     time.Duration // сколько удалённые задачи лежат в корзине
+	TrashPurgeInterval time.Duration // как часто чистить корзину
+
+	AutoArchiveAfter    time.Duration // выполненные задачи старше уходят в архив (0 = выключено)
+	AutoArchiveInterval time.Duration // как часто запускать автоархивацию
 }
 
 func Load() (Config, error) { // читаем env -> Config
@@ -22,8 +46,134 @@ func Load() (Config, error) { // читаем env -> Config
 		return Config{}, fmt.Errorf("DATABASE_URL is required") // ошибка
 	}
 
+	secret, ok := os.LookupEnv("JWT_SECRET")        // JWT_SECRET из env
+	if !ok || len(strings.TrimSpace(secret)) < 32 { // обязателен и не короткий
+		return Config{}, fmt.Errorf("JWT_SECRET is required (min 32 chars)") // ошибка
+	}
+
+	accessTTL, err := durationEnv("ACCESS_TOKEN_TTL", 15*time.Minute) // опционально
+	if err != nil {
+		return Config{}, err
+	}
+	refreshTTL, err := durationEnv("REFRESH_TOKEN_TTL", 30*24*time.Hour) // опционально
+	if err != nil {
+		return Config{}, err
+	}
+
+	inviteTTL, err := durationEnv("WORKSPACE_INVITE_TTL", 7*24*time.Hour) // опционально
+	if err != nil {
+		return Config{}, err
+	}
+
+	maxBytes, err := sizeEnv("ATTACHMENT_MAX_BYTES", 25<<20) // опционально, 25 MiB
+	if err != nil {
+		return Config{}, err
+	}
+
+	backend := strings.ToLower(strings.TrimSpace(os.Getenv("BLOB_BACKEND"))) // local (по умолчанию) | s3
+	if backend == "" {
+		backend = "local"
+	}
+	if backend != "local" && backend != "s3" {
+		return Config{}, fmt.Errorf("BLOB_BACKEND must be local or s3") // ошибка
+	}
+	blobDir := strings.TrimSpace(os.Getenv("BLOB_DIR")) // папка для local
+	if blobDir == "" {
+		blobDir = "./data/blobs"
+	}
+
+	trashRetention, err := durationEnv("TRASH_RETENTION", 30*24*time.Hour) // опционально
+	if err != nil {
+		return Config{}, err
+	}
+	purgeInterval, err := durationEnv("TRASH_PURGE_INTERVAL", time.Hour) // опционально
+	if err != nil {
+		return Config{}, err
+	}
+
+	autoArchiveAfter, err := switchableDurationEnv("AUTO_ARCHIVE_AFTER", 0) // опционально, по умолчанию выключено (0)
+	if err != nil {
+		return Config{}, err
+	}
+	autoArchiveInterval, err := durationEnv("AUTO_ARCHIVE_INTERVAL", time.Hour) // опционально
+	if err != nil {
+		return Config{}, err
+	}
+
+	autoComplete := false                                                          // CHECKLIST_AUTO_COMPLETE=true (опц.)
+	if raw := strings.TrimSpace(os.Getenv("CHECKLIST_AUTO_COMPLETE")); raw != "" { // задано
+		if autoComplete, err = strconv.ParseBool(raw); err != nil {
+			return Config{}, fmt.Errorf("CHECKLIST_AUTO_COMPLETE must be true or false") // ошибка
+		}
+	}
+
+	var admins []string                                               // ADMIN_EMAILS=a@x.io,b@x.io (опц.)
+	for _, e := range strings.Split(os.Getenv("ADMIN_EMAILS"), ",") { // по запятой
+		if e = strings.ToLower(strings.TrimSpace(e)); e != "" { // чистим
+			admins = append(admins, e)
+		}
+	}
+
 	return Config{ // собираем конфиг
-		Port:        strings.TrimSpace(port),  // чистим пробелы
-		DatabaseURL: strings.TrimSpace(dbURL), // чистим пробелы
+		Port:            strings.TrimSpace(port),   // чистим пробелы
+		DatabaseURL:     strings.TrimSpace(dbURL),  // чистим пробелы
+		JWTSecret:       strings.TrimSpace(secret), // чистим пробелы
+		AccessTokenTTL:  accessTTL,                 // TTL access
+		RefreshTokenTTL: refreshTTL,                // TTL refresh
+		AdminEmails:     admins,                    // админы
+		InviteTTL:       inviteTTL,                 // TTL приглашений
+
+		BlobBackend:        backend,                                     // хранилище вложений
+		BlobDir:            blobDir,                                     // папка local
+		S3Endpoint:         strings.TrimSpace(os.Getenv("S3_ENDPOINT")), // S3 (проверяет конструктор)
+		S3Region:           strings.TrimSpace(os.Getenv("S3_REGION")),
+		S3Bucket:           strings.TrimSpace(os.Getenv("S3_BUCKET")),
+		S3AccessKey:        strings.TrimSpace(os.Getenv("S3_ACCESS_KEY")),
+		S3SecretKey:        strings.TrimSpace(os.Getenv("S3_SECRET_KEY")),
+		AttachmentMaxBytes: maxBytes, // лимит вложения
+
+		ChecklistAutoComplete: autoComplete, // автозакрытие по чек-листу
+
+		TrashRetention:     trashRetention, // срок корзины
+		TrashPurgeInterval: purgeInterval,  // период очистки
+
+		AutoArchiveAfter:    autoArchiveAfter,    // возраст для автоархивации
+		AutoArchiveInterval: autoArchiveInterval, // период автоархивации
 	}, nil
 }
+
+func durationEnv(key string, def time.Duration) (time.Duration, error) { // необязательная длительность
+	raw := strings.TrimSpace(os.Getenv(key)) // значение из env
+	if raw == "" {                           // не задано
+		return def, nil // дефолт
+	}
+	d, err := time.ParseDuration(raw) // "15m", "720h"...
+	if err != nil || d <= 0 {         // мусор / <=0
+		return 0, fmt.Errorf("%s must be a positive duration", key) // ошибка
+	}
+	return d, nil // ok
+}
+
+func switchableDurationEnv(key string, def time.Duration) (time.Duration, error) { // необязательная длительность, 0 = выключено
+	raw := strings.TrimSpace(os.Getenv(key)) // значение из env
+	if raw == "" {                           // не задано
+		return def, nil // дефолт
+	}
+	d, err := time.ParseDuration(raw) // "0", "720h"...
+	if err != nil || d < 0 {          // мусор / <0
+		return 0, fmt.Errorf("%s must be a non-negative duration (0 = off)", key) // ошибка
+	}
+	return d, nil // ok
+}
+
+func sizeEnv(key string, def int64) (int64, error) { // необязательный размер в байтах
+	raw := strings.TrimSpace(os.Getenv(key)) // значение из env
+	if raw == "" {                           // не задано
+		return def, nil // дефолт
+	}
+	n, err := strconv.ParseInt(raw, 10, 64) // байты
+	if err != nil || n <= 0 {               // мусор / <=0
+		return 0, fmt.Errorf("%s must be a positive number of bytes", key) // ошибка
+	}
+	return n, nil // ok
+}
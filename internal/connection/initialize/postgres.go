@@ -8,7 +8,9 @@ import (
 )
 
 func New(databaseURL string) (*gorm.DB, error) { // создать подключение
-	gormDB, err := gorm.Open(postgres.Open(databaseURL), &gorm.Config{}) // открыть Postgres
+	gormDB, err := gorm.Open(postgres.Open(databaseURL), &gorm.Config{
+		TranslateError: true, // ошибки драйвера -> gorm.ErrDuplicatedKey и т.п.
+	}) // открыть Postgres
 	if err != nil { // если не удалось
 		return nil, fmt.Errorf("open db: %w", err) // вернуть ошибку
 	}
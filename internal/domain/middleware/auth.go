@@ -0,0 +1,98 @@
+package middleware // middleware слой
+
+import (
+	"net/http" // HTTP статусы
+	"strings"  // разбор заголовка
+
+	"github.com/gin-gonic/gin" // Gin
+
+	"task-tracker/internal/api/rest/response" // JSON ошибки
+	"task-tracker/internal/domain/service"    // проверка токена/ключа
+	"task-tracker/internal/domain/types"      // модели
+)
+
+const userKey = "auth.user" // ключ пользователя в gin.Context
+
+func Auth(authService *service.AuthService, apiKeyService *service.APIKeyService) gin.HandlerFunc { // требует access-токен или API-ключ
+	return func(c *gin.Context) { // middleware
+		scheme, token, _ := strings.Cut(c.GetHeader("Authorization"), " ") // "Bearer <jwt>" / "ApiKey <key>"
+		token = strings.TrimSpace(token)
+		if token == "" || (scheme != "Bearer" && scheme != "ApiKey") { // нет заголовка
+			c.Header("WWW-Authenticate", "Bearer") // подсказка клиенту
+			response.JSONError(c, http.StatusUnauthorized, string(service.CodeUnauthorized), map[string]string{"token": "required"})
+			c.Abort() // дальше не идём
+			return
+		}
+
+		var (
+			user  *types.User
+			actor service.Actor
+			err   error
+		)
+		if scheme == "ApiKey" || service.IsAPIKey(token) { // ключ для скриптов/CI
+			var key *types.APIKey
+			user, key, err = apiKeyService.Authenticate(c.Request.Context(), token)
+			if err == nil {
+				actor = service.Actor{UserID: user.ID, Role: user.Role, APIKeyID: key.ID, Scopes: key.ScopeList()}
+			}
+		} else { // интерактивная сессия
+			user, err = authService.Authenticate(c.Request.Context(), token)
+			if err == nil {
+				actor = service.Actor{UserID: user.ID, Role: user.Role}
+			}
+		}
+		if err != nil { // невалиден / ошибка БД
+			c.Header("WWW-Authenticate", "Bearer")
+			response.FromServiceError(c, err)
+			c.Abort()
+			return
+		}
+
+		c.Set(userKey, user)                                                             // кладём пользователя в контекст
+		c.Request = c.Request.WithContext(service.WithActor(c.Request.Context(), actor)) // для ограничения выборок в сервисах
+		c.Next()                                                                         // выполнить хендлеры
+	}
+}
+
+func RequireScope(scope string) gin.HandlerFunc { // право API-ключа на группу роутов
+	return func(c *gin.Context) { // middleware
+		actor, ok := service.ActorFrom(c.Request.Context()) // кладётся Auth
+		if !ok {
+			response.JSONError(c, http.StatusUnauthorized, string(service.CodeUnauthorized), nil)
+			c.Abort()
+			return
+		}
+		if !actor.HasScope(scope) { // у ключа нет права
+			response.JSONError(c, http.StatusForbidden, string(service.CodeForbidden), map[string]string{"scope": scope + " required"})
+			c.Abort()
+			return
+		}
+		c.Next() // выполнить хендлеры
+	}
+}
+
+func RequireSession() gin.HandlerFunc { // только интерактивная сессия (не API-ключ)
+	return func(c *gin.Context) { // middleware
+		actor, ok := service.ActorFrom(c.Request.Context()) // кладётся Auth
+		if !ok {
+			response.JSONError(c, http.StatusUnauthorized, string(service.CodeUnauthorized), nil)
+			c.Abort()
+			return
+		}
+		if actor.APIKeyID != 0 { // ключом нельзя управлять ключами
+			response.JSONError(c, http.StatusForbidden, string(service.CodeForbidden), map[string]string{"auth": "session required"})
+			c.Abort()
+			return
+		}
+		c.Next() // выполнить хендлеры
+	}
+}
+
+func CurrentUser(c *gin.Context) (*types.User, bool) { // пользователь, положенный Auth
+	v, ok := c.Get(userKey) // достать из контекста
+	if !ok {
+		return nil, false
+	}
+	user, ok := v.(*types.User) // привести тип
+	return user, ok
+}
@@ -0,0 +1,60 @@
+package repository // реализации репозиториев
+
+import (
+	"context" // ctx
+	"errors"  // errors.Is
+	"time"    // now
+
+	"task-tracker/internal/domain/types" // модели
+
+	"gorm.io/gorm" // GORM
+)
+
+type APIKeyGormRepository struct { // repo API-ключей на GORM
+	db *gorm.DB // подключение
+}
+
+func NewAPIKeyGormRepository(db *gorm.DB) *APIKeyGormRepository { // конструктор
+	return &APIKeyGormRepository{db: db} // сохранить db
+}
+
+func (r *APIKeyGormRepository) Create(ctx context.Context, key *types.APIKey) error { // сохранить ключ
+	return conn(ctx, r.db).Create(key).Error // INSERT api_key
+}
+
+func (r *APIKeyGormRepository) ListByUser(ctx context.Context, userID uint) ([]types.APIKey, error) { // ключи пользователя
+	var keys []types.APIKey                                                           // результат
+	err := conn(ctx, r.db).Where("user_id = ?", userID).Order("id").Find(&keys).Error // SELECT ... WHERE user_id=?
+	return keys, err                                                                  // вернуть
+}
+
+func (r *APIKeyGormRepository) GetByHash(ctx context.Context, hash string) (*types.APIKey, error) { // найти по хешу
+	var key types.APIKey                                                 // объект
+	err := conn(ctx, r.db).Where("key_hash = ?", hash).First(&key).Error // SELECT ... WHERE key_hash=?
+	if err != nil {                                                      // обработка ошибок
+		if errors.Is(err, gorm.ErrRecordNotFound) { // нет записи
+			return nil, ErrNotFound // доменная not found
+		}
+		return nil, err // прочие ошибки
+	}
+	return &key, nil // вернуть ключ
+}
+
+func (r *APIKeyGormRepository) Revoke(ctx context.Context, userID, id uint) error { // отозвать ключ владельца
+	res := conn(ctx, r.db).Model(&types.APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID). // только свой активный
+		Update("revoked_at", time.Now())                                    // UPDATE ... SET revoked_at=now
+	if res.Error != nil { // ошибка
+		return res.Error
+	}
+	if res.RowsAffected == 0 { // чужой / нет / уже отозван
+		return ErrNotFound
+	}
+	return nil // ok
+}
+
+func (r *APIKeyGormRepository) TouchLastUsed(ctx context.Context, id uint, at time.Time) error { // отметить использование
+	return conn(ctx, r.db).Model(&types.APIKey{}).
+		Where("id = ?", id).             // WHERE id=?
+		Update("last_used_at", at).Error // UPDATE ... SET last_used_at=?
+}
@@ -0,0 +1,16 @@
+package repository // интерфейс репозитория API-ключей
+
+import (
+	"context" // ctx
+	"time"    // last_used_at
+
+	"task-tracker/internal/domain/types" // модели
+)
+
+type APIKeyRepository interface { // контракт хранилища API-ключей
+	Create(ctx context.Context, key *types.APIKey) error                 // сохранить
+	ListByUser(ctx context.Context, userID uint) ([]types.APIKey, error) // ключи пользователя
+	GetByHash(ctx context.Context, hash string) (*types.APIKey, error)   // найти по хешу
+	Revoke(ctx context.Context, userID, id uint) error                   // отозвать свой ключ
+	TouchLastUsed(ctx context.Context, id uint, at time.Time) error      // отметить использование
+}
@@ -0,0 +1,51 @@
+package repository // реализации репозиториев
+
+import (
+	"context" // ctx
+	"errors"  // errors.Is
+
+	"task-tracker/internal/domain/types" // модели
+
+	"gorm.io/gorm" // GORM
+)
+
+type AttachmentGormRepository struct { // repo вложений на GORM
+	db *gorm.DB // подключение
+}
+
+func NewAttachmentGormRepository(db *gorm.DB) *AttachmentGormRepository { // конструктор
+	return &AttachmentGormRepository{db: db} // сохранить db
+}
+
+func (r *AttachmentGormRepository) Create(ctx context.Context, a *types.Attachment) error { // сохранить метаданные
+	return conn(ctx, r.db).Omit("Task", "Uploader").Create(a).Error // INSERT attachment
+}
+
+func (r *AttachmentGormRepository) ListByTask(ctx context.Context, taskID uint) ([]types.Attachment, error) { // вложения задачи
+	var list []types.Attachment
+	err := conn(ctx, r.db).Where("task_id = ?", taskID).Order("id").Find(&list).Error
+	return list, err
+}
+
+func (r *AttachmentGormRepository) GetByID(ctx context.Context, taskID, id uint) (*types.Attachment, error) { // получить по id
+	var a types.Attachment
+	err := conn(ctx, r.db).Where("task_id = ?", taskID).First(&a, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) { // нет записи
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *AttachmentGormRepository) Delete(ctx context.Context, taskID, id uint) error { // удалить строку
+	res := conn(ctx, r.db).Where("task_id = ?", taskID).Delete(&types.Attachment{}, id)
+	if res.Error != nil { // ошибка
+		return res.Error
+	}
+	if res.RowsAffected == 0 { // не удалилось
+		return ErrNotFound
+	}
+	return nil // ok
+}
@@ -0,0 +1,14 @@
+package repository // интерфейс репозитория вложений
+
+import (
+	"context" // ctx
+
+	"task-tracker/internal/domain/types" // модели
+)
+
+type AttachmentRepository interface { // контракт хранилища метаданных вложений
+	Create(ctx context.Context, a *types.Attachment) error                   // сохранить
+	ListByTask(ctx context.Context, taskID uint) ([]types.Attachment, error) // вложения задачи
+	GetByID(ctx context.Context, taskID, id uint) (*types.Attachment, error) // получить
+	Delete(ctx context.Context, taskID, id uint) error                       // удалить строку
+}
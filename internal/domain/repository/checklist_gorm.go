@@ -0,0 +1,90 @@
+package repository // реализации репозиториев
+
+import (
+	"context"      // ctx
+	"database/sql" // NullInt64
+	"errors"       // errors.Is
+
+	"task-tracker/internal/domain/types" // модели
+
+	"gorm.io/gorm" // GORM
+)
+
+type ChecklistGormRepository struct { // repo чек-листов на GORM
+	db *gorm.DB // подключение
+}
+
+func NewChecklistGormRepository(db *gorm.DB) *ChecklistGormRepository { // конструктор
+	return &ChecklistGormRepository{db: db} // сохранить db
+}
+
+func (r *ChecklistGormRepository) Create(ctx context.Context, item *types.ChecklistItem) error { // добавить в конец
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error { // позиция и вставка вместе
+		var last sql.NullInt64 // NULL — пунктов ещё нет
+		if err := tx.Model(&types.ChecklistItem{}).Where("task_id = ?", item.TaskID).
+			Select("MAX(position)").Row().Scan(&last); err != nil {
+			return err
+		}
+		item.Position = 0
+		if last.Valid { // после последнего
+			item.Position = int(last.Int64) + 1
+		}
+		return tx.Create(item).Error
+	})
+}
+
+func (r *ChecklistGormRepository) List(ctx context.Context, taskID uint) ([]types.ChecklistItem, error) { // пункты по порядку
+	var items []types.ChecklistItem
+	err := conn(ctx, r.db).Where("task_id = ?", taskID).Order("position, id").Find(&items).Error
+	return items, err
+}
+
+func (r *ChecklistGormRepository) GetByID(ctx context.Context, taskID, id uint) (*types.ChecklistItem, error) { // получить по id
+	var item types.ChecklistItem
+	err := conn(ctx, r.db).Where("task_id = ?", taskID).First(&item, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) { // нет записи
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *ChecklistGormRepository) Save(ctx context.Context, item *types.ChecklistItem) error { // сохранить изменения
+	return conn(ctx, r.db).Save(item).Error
+}
+
+func (r *ChecklistGormRepository) Delete(ctx context.Context, taskID, id uint) error { // удалить и сомкнуть позиции
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var item types.ChecklistItem
+		if err := tx.Where("task_id = ?", taskID).First(&item, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNotFound
+			}
+			return err
+		}
+		if err := tx.Delete(&item).Error; err != nil {
+			return err
+		}
+		// хвост на одну позицию вверх
+		return tx.Model(&types.ChecklistItem{}).
+			Where("task_id = ? AND position > ?", taskID, item.Position).
+			Update("position", gorm.Expr("position - 1")).Error
+	})
+}
+
+func (r *ChecklistGormRepository) Reorder(ctx context.Context, taskID uint, ids []uint) error { // новый порядок
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		for pos, id := range ids {
+			res := tx.Model(&types.ChecklistItem{}).Where("task_id = ? AND id = ?", taskID, id).Update("position", pos)
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 { // пункт пропал — откатываем всё
+				return ErrNotFound
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,16 @@
+package repository // интерфейс репозитория чек-листов
+
+import (
+	"context" // ctx
+
+	"task-tracker/internal/domain/types" // модели
+)
+
+type ChecklistRepository interface { // контракт хранилища пунктов чек-листа
+	Create(ctx context.Context, item *types.ChecklistItem) error                // добавить в конец
+	List(ctx context.Context, taskID uint) ([]types.ChecklistItem, error)       // пункты по порядку
+	GetByID(ctx context.Context, taskID, id uint) (*types.ChecklistItem, error) // получить
+	Save(ctx context.Context, item *types.ChecklistItem) error                  // сохранить изменения
+	Delete(ctx context.Context, taskID, id uint) error                          // удалить (остальные сдвигаются)
+	Reorder(ctx context.Context, taskID uint, ids []uint) error                 // расставить в порядке ids (атомарно)
+}
@@ -0,0 +1,94 @@
+package repository // реализации репозиториев
+
+import (
+	"context" // ctx
+	"errors"  // errors.Is
+	"time"    // edited_at
+
+	"task-tracker/internal/domain/types" // модели
+
+	"gorm.io/gorm" // GORM
+)
+
+type CommentGormRepository struct { // repo комментариев на GORM
+	db *gorm.DB // подключение
+}
+
+func NewCommentGormRepository(db *gorm.DB) *CommentGormRepository { // конструктор
+	return &CommentGormRepository{db: db} // сохранить db
+}
+
+func (r *CommentGormRepository) Create(ctx context.Context, c *types.Comment) error { // создать комментарий
+	return conn(ctx, r.db).Omit("Task", "Author", "Parent").Create(c).Error // INSERT comment
+}
+
+func (r *CommentGormRepository) List(ctx context.Context, taskID uint, parentID *uint, afterID uint, limit int) ([]types.Comment, error) { // страница комментариев
+	var list []types.Comment // результат
+
+	q := conn(ctx, r.db).Preload("Author").Where("task_id = ?", taskID).Order("id") // хронологически
+	if parentID != nil {                                                            // одна ветка
+		q = q.Where("parent_id = ?", *parentID)
+	}
+	if afterID > 0 { // курсор
+		q = q.Where("id > ?", afterID)
+	}
+	if limit > 0 { // лимит
+		q = q.Limit(limit)
+	}
+
+	err := q.Find(&list).Error // выполнить SELECT
+	return list, err           // вернуть
+}
+
+func (r *CommentGormRepository) GetByID(ctx context.Context, taskID, id uint) (*types.Comment, error) { // получить по id
+	var c types.Comment
+	err := conn(ctx, r.db).Preload("Author").Where("task_id = ?", taskID).First(&c, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) { // нет записи
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *CommentGormRepository) UpdateBody(ctx context.Context, id uint, body string, editedAt time.Time) error { // правка текста
+	res := conn(ctx, r.db).Model(&types.Comment{}).Where("id = ?", id).
+		Updates(map[string]any{"body": body, "edited_at": editedAt})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *CommentGormRepository) HasReplies(ctx context.Context, id uint) (bool, error) { // есть ли ответы
+	var n int64
+	err := conn(ctx, r.db).Model(&types.Comment{}).Where("parent_id = ?", id).Limit(1).Count(&n).Error
+	return n > 0, err
+}
+
+func (r *CommentGormRepository) Tombstone(ctx context.Context, id uint, at time.Time) error { // заглушка: ветка остаётся
+	res := conn(ctx, r.db).Model(&types.Comment{}).Where("id = ?", id).
+		Updates(map[string]any{"body": "", "deleted_at": at})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *CommentGormRepository) Delete(ctx context.Context, id uint) error { // удалить по id
+	res := conn(ctx, r.db).Delete(&types.Comment{}, id) // DELETE ... WHERE id=?
+	if res.Error != nil {                               // ошибка
+		return res.Error
+	}
+	if res.RowsAffected == 0 { // не удалилось
+		return ErrNotFound
+	}
+	return nil // ok
+}
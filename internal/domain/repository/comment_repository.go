@@ -0,0 +1,18 @@
+package repository // интерфейс репозитория комментариев
+
+import (
+	"context" // ctx
+	"time"    // edited_at
+
+	"task-tracker/internal/domain/types" // модели
+)
+
+type CommentRepository interface { // контракт хранилища комментариев
+	Create(ctx context.Context, c *types.Comment) error                                                      // создать
+	List(ctx context.Context, taskID uint, parentID *uint, afterID uint, limit int) ([]types.Comment, error) // страница по id (с Author)
+	GetByID(ctx context.Context, taskID, id uint) (*types.Comment, error)                                    // получить (с Author)
+	UpdateBody(ctx context.Context, id uint, body string, editedAt time.Time) error                          // правка текста
+	HasReplies(ctx context.Context, id uint) (bool, error)                                                   // есть ли ответы
+	Tombstone(ctx context.Context, id uint, at time.Time) error                                              // оставить заглушку вместо текста
+	Delete(ctx context.Context, id uint) error                                                               // удалить совсем
+}
@@ -0,0 +1,117 @@
+package repository // реализации репозиториев
+
+import (
+	"context"       // ctx
+	"encoding/json" // вариант multi_select для @>
+	"errors"        // errors.Is
+	"time"          // updated_at задач
+
+	"task-tracker/internal/domain/types" // модели
+
+	"gorm.io/gorm"        // GORM
+	"gorm.io/gorm/clause" // upsert
+)
+
+type CustomFieldGormRepository struct { // repo пользовательских полей на GORM
+	db *gorm.DB // подключение
+}
+
+func NewCustomFieldGormRepository(db *gorm.DB) *CustomFieldGormRepository { // конструктор
+	return &CustomFieldGormRepository{db: db} // сохранить db
+}
+
+func (r *CustomFieldGormRepository) Create(ctx context.Context, f *types.CustomField) error { // создать поле
+	err := conn(ctx, r.db).Omit("Workspace").Create(f).Error // INSERT custom_field
+	if errors.Is(err, gorm.ErrDuplicatedKey) {               // ключ занят
+		return ErrDuplicate
+	}
+	return err
+}
+
+func (r *CustomFieldGormRepository) List(ctx context.Context, workspaceID uint) ([]types.CustomField, error) { // поля пространства
+	var list []types.CustomField
+	err := conn(ctx, r.db).Where("workspace_id = ?", workspaceID).Order("id").Find(&list).Error
+	return list, err
+}
+
+func (r *CustomFieldGormRepository) GetByID(ctx context.Context, workspaceID, id uint) (*types.CustomField, error) { // получить по id
+	var f types.CustomField
+	err := conn(ctx, r.db).Where("workspace_id = ?", workspaceID).First(&f, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) { // нет записи
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &f, nil
+}
+
+func (r *CustomFieldGormRepository) GetByKeys(ctx context.Context, workspaceID uint, keys []string) ([]types.CustomField, error) { // по ключам
+	var list []types.CustomField
+	if len(keys) == 0 {
+		return list, nil
+	}
+	err := conn(ctx, r.db).Where("workspace_id = ? AND key IN ?", workspaceID, keys).Find(&list).Error
+	return list, err
+}
+
+func (r *CustomFieldGormRepository) Count(ctx context.Context, workspaceID uint) (int64, error) { // сколько полей
+	var n int64
+	err := conn(ctx, r.db).Model(&types.CustomField{}).Where("workspace_id = ?", workspaceID).Count(&n).Error
+	return n, err
+}
+
+func (r *CustomFieldGormRepository) Update(ctx context.Context, f *types.CustomField) error { // сохранить
+	return conn(ctx, r.db).Omit("Workspace").Save(f).Error
+}
+
+func (r *CustomFieldGormRepository) Delete(ctx context.Context, workspaceID, id uint) error { // удалить поле
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error { // поле и карточки задач со значениями — вместе
+		err := tx.Unscoped().Model(&types.Task{}).
+			Where("id IN (SELECT task_id FROM task_field_values WHERE field_id = ?)", id). // значение пропадёт из карточки
+			UpdateColumns(map[string]any{"version": gorm.Expr("version + 1"), "updated_at": time.Now()}).Error
+		if err != nil {
+			return err
+		}
+		res := tx.Where("workspace_id = ?", workspaceID).Delete(&types.CustomField{}, id)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
+
+func (r *CustomFieldGormRepository) OptionInUse(ctx context.Context, fieldID uint, option string) (bool, error) { // вариант где-то выбран
+	multi, err := json.Marshal([]string{option}) // ["x"] для jsonb @>
+	if err != nil {
+		return false, err
+	}
+	var n int64
+	err = conn(ctx, r.db).Model(&types.TaskFieldValue{}).
+		Where("field_id = ? AND (text_value = ? OR multi_value @> ?::jsonb)", fieldID, option, string(multi)).
+		Limit(1).Count(&n).Error
+	return n > 0, err
+}
+
+func (r *CustomFieldGormRepository) SetValues(ctx context.Context, taskID uint, set []types.TaskFieldValue, clearIDs []uint) error { // записать значения
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error { // все поля или ни одного
+		if len(clearIDs) > 0 {
+			if err := tx.Where("task_id = ? AND field_id IN ?", taskID, clearIDs).Delete(&types.TaskFieldValue{}).Error; err != nil {
+				return err
+			}
+		}
+		if len(set) == 0 {
+			return nil
+		}
+		for i := range set {
+			set[i].TaskID = taskID
+		}
+		return tx.Omit(clause.Associations).Clauses(clause.OnConflict{ // (task_id, field_id) уже есть — перезаписать
+			Columns:   []clause.Column{{Name: "task_id"}, {Name: "field_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"text_value", "number_value", "date_value", "user_value", "multi_value", "updated_at"}),
+		}).Create(&set).Error
+	})
+}
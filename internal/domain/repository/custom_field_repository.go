@@ -0,0 +1,19 @@
+package repository // интерфейс репозитория пользовательских полей
+
+import (
+	"context" // ctx
+
+	"task-tracker/internal/domain/types" // модели
+)
+
+type CustomFieldRepository interface { // контракт хранилища полей и их значений
+	Create(ctx context.Context, f *types.CustomField) error                                        // создать (ErrDuplicate — ключ занят)
+	List(ctx context.Context, workspaceID uint) ([]types.CustomField, error)                       // поля пространства
+	GetByID(ctx context.Context, workspaceID, id uint) (*types.CustomField, error)                 // получить
+	GetByKeys(ctx context.Context, workspaceID uint, keys []string) ([]types.CustomField, error)   // найти по ключам (неизвестные пропускаются)
+	Count(ctx context.Context, workspaceID uint) (int64, error)                                    // сколько полей в пространстве
+	Update(ctx context.Context, f *types.CustomField) error                                        // сохранить имя/варианты
+	Delete(ctx context.Context, workspaceID, id uint) error                                        // удалить (значения каскадно, их задачи получают новую версию)
+	OptionInUse(ctx context.Context, fieldID uint, option string) (bool, error)                    // вариант выбран хоть у одной задачи
+	SetValues(ctx context.Context, taskID uint, set []types.TaskFieldValue, clearIDs []uint) error // записать/очистить значения задачи (атомарно)
+}
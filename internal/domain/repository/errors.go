@@ -2,4 +2,9 @@ package repository // пакет репозиториев
 
 import "errors" // errors.New
 
-var ErrNotFound = errors.New("not found") // общая ошибка "не найдено"
+var (
+	ErrNotFound   = errors.New("not found")            // общая ошибка "не найдено"
+	ErrDuplicate  = errors.New("duplicate")            // нарушение уникальности
+	ErrReferenced = errors.New("referenced by others") // на запись ссылаются (FK)
+	ErrStale      = errors.New("stale version")        // запись успели изменить (версия не совпала)
+)
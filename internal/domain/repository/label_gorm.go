@@ -0,0 +1,125 @@
+package repository // реализации репозиториев
+
+import (
+	"context" // ctx
+	"errors"  // errors.Is
+	"time"    // updated_at задач
+
+	"task-tracker/internal/domain/types" // модели
+
+	"gorm.io/gorm" // GORM
+)
+
+type LabelGormRepository struct { // repo меток на GORM
+	db *gorm.DB // подключение
+}
+
+func NewLabelGormRepository(db *gorm.DB) *LabelGormRepository { // конструктор
+	return &LabelGormRepository{db: db} // сохранить db
+}
+
+func scopeLabels(q *gorm.DB, scope Scope) *gorm.DB { // ограничить метки доступными вызывающему
+	if scope.All { // админ видит всё
+		return q
+	}
+	return q.Where( // свои личные + метки пространств, где он участник
+		"(labels.workspace_id IS NULL AND labels.user_id = ?) OR labels.workspace_id IN (SELECT workspace_id FROM workspace_members WHERE user_id = ?)",
+		scope.UserID, scope.UserID,
+	)
+}
+
+func (r *LabelGormRepository) Create(ctx context.Context, label *types.Label) error { // создать метку
+	err := conn(ctx, r.db).Omit("Workspace").Create(label).Error // INSERT label
+	if errors.Is(err, gorm.ErrDuplicatedKey) {                   // имя занято
+		return ErrDuplicate
+	}
+	return err
+}
+
+func (r *LabelGormRepository) List(ctx context.Context, scope Scope, workspaceID *uint) ([]types.Label, error) { // список меток
+	var labels []types.Label // результат
+
+	q := scopeLabels(conn(ctx, r.db).Model(&types.Label{}), scope).Order("name, id") // базовый запрос
+	if workspaceID != nil {                                                          // только пространство
+		q = q.Where("workspace_id = ?", *workspaceID)
+	}
+
+	err := q.Find(&labels).Error // выполнить SELECT
+	return labels, err           // вернуть
+}
+
+func (r *LabelGormRepository) GetByID(ctx context.Context, scope Scope, id uint) (*types.Label, error) { // получить по id
+	var label types.Label                                              // объект
+	err := scopeLabels(conn(ctx, r.db), scope).First(&label, id).Error // SELECT ... WHERE id=?
+	if err != nil {                                                    // обработка ошибок
+		if errors.Is(err, gorm.ErrRecordNotFound) { // нет записи
+			return nil, ErrNotFound // доменная not found
+		}
+		return nil, err // прочие ошибки
+	}
+	return &label, nil // вернуть
+}
+
+func (r *LabelGormRepository) Update(ctx context.Context, id uint, name, color *string) (*types.Label, error) { // частичный апдейт
+	label, err := r.GetByID(ctx, Scope{All: true}, id) // доступ проверил сервис
+	if err != nil {
+		return nil, err // ErrNotFound уже тут
+	}
+	if name != nil { // новое имя
+		label.Name = *name
+	}
+	if color != nil { // новый цвет
+		label.Color = *color
+	}
+	err = conn(ctx, r.db).Transaction(func(tx *gorm.DB) error { // метка и карточки её задач — вместе
+		if err := tx.Omit("Workspace").Save(label).Error; err != nil { // сохранить
+			return err
+		}
+		return touchLabelled(tx, id)
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) { // имя занято
+			return nil, ErrDuplicate
+		}
+		return nil, err
+	}
+	return label, nil // вернуть
+}
+
+func (r *LabelGormRepository) Delete(ctx context.Context, id uint) error { // удалить по id
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error { // метка и карточки её задач — вместе
+		if err := touchLabelled(tx, id); err != nil { // пока task_labels на месте
+			return err
+		}
+		res := tx.Delete(&types.Label{}, id) // DELETE ... (task_labels каскадом)
+		if res.Error != nil {                // ошибка
+			return res.Error
+		}
+		if res.RowsAffected == 0 { // не удалилось
+			return ErrNotFound
+		}
+		return nil // ok
+	})
+}
+
+func touchLabelled(tx *gorm.DB, labelID uint) error { // метка показана в карточках задач: новая версия и updated_at (иначе If-Modified-Since даст 304)
+	return tx.Unscoped().Model(&types.Task{}).Where("id IN (SELECT task_id FROM task_labels WHERE label_id = ?)", labelID).
+		UpdateColumns(map[string]any{"version": gorm.Expr("version + 1"), "updated_at": time.Now()}).Error
+}
+
+func (r *LabelGormRepository) Attach(ctx context.Context, taskID, labelID uint) error { // повесить метку
+	return conn(ctx, r.db).Exec( // уже висит — не ошибка
+		"INSERT INTO task_labels (task_id, label_id) VALUES (?, ?) ON CONFLICT DO NOTHING", taskID, labelID,
+	).Error
+}
+
+func (r *LabelGormRepository) Detach(ctx context.Context, taskID, labelID uint) error { // снять метку
+	res := conn(ctx, r.db).Exec("DELETE FROM task_labels WHERE task_id = ? AND label_id = ?", taskID, labelID)
+	if res.Error != nil { // ошибка
+		return res.Error
+	}
+	if res.RowsAffected == 0 { // и не висела
+		return ErrNotFound
+	}
+	return nil // ok
+}
@@ -0,0 +1,17 @@
+package repository // интерфейс репозитория меток
+
+import (
+	"context" // ctx
+
+	"task-tracker/internal/domain/types" // модели
+)
+
+type LabelRepository interface { // контракт хранилища меток
+	Create(ctx context.Context, label *types.Label) error                            // создать (ErrDuplicate — имя занято)
+	List(ctx context.Context, scope Scope, workspaceID *uint) ([]types.Label, error) // доступные метки (nil = все)
+	GetByID(ctx context.Context, scope Scope, id uint) (*types.Label, error)         // получить
+	Update(ctx context.Context, id uint, name, color *string) (*types.Label, error)  // переименовать/перекрасить (задачи с меткой получают новую версию)
+	Delete(ctx context.Context, id uint) error                                       // удалить (снимается со всех задач, они получают новую версию)
+	Attach(ctx context.Context, taskID, labelID uint) error                          // повесить на задачу (идемпотентно)
+	Detach(ctx context.Context, taskID, labelID uint) error                          // снять с задачи (ErrNotFound — не висела)
+}
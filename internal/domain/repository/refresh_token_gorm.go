@@ -0,0 +1,54 @@
+package repository // реализации репозиториев
+
+import (
+	"context" // ctx
+	"errors"  // errors.Is
+	"time"    // now
+
+	"task-tracker/internal/domain/types" // модели
+
+	"gorm.io/gorm" // GORM
+)
+
+type RefreshTokenGormRepository struct { // repo refresh-токенов на GORM
+	db *gorm.DB // подключение
+}
+
+func NewRefreshTokenGormRepository(db *gorm.DB) *RefreshTokenGormRepository { // конструктор
+	return &RefreshTokenGormRepository{db: db} // сохранить db
+}
+
+func (r *RefreshTokenGormRepository) Create(ctx context.Context, token *types.RefreshToken) error { // сохранить токен
+	return conn(ctx, r.db).Create(token).Error // INSERT refresh_token
+}
+
+func (r *RefreshTokenGormRepository) GetByHash(ctx context.Context, hash string) (*types.RefreshToken, error) { // найти по хешу
+	var token types.RefreshToken                                             // объект
+	err := conn(ctx, r.db).Where("token_hash = ?", hash).First(&token).Error // SELECT ... WHERE token_hash=?
+	if err != nil {                                                          // обработка ошибок
+		if errors.Is(err, gorm.ErrRecordNotFound) { // нет записи
+			return nil, ErrNotFound // доменная not found
+		}
+		return nil, err // прочие ошибки
+	}
+	return &token, nil // вернуть токен
+}
+
+func (r *RefreshTokenGormRepository) Revoke(ctx context.Context, id uint) error { // отозвать один токен
+	res := conn(ctx, r.db).Model(&types.RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", id). // только активный — защита от гонки при ротации
+		Update("revoked_at", time.Now())            // UPDATE ... SET revoked_at=now
+	if res.Error != nil { // ошибка
+		return res.Error
+	}
+	if res.RowsAffected == 0 { // уже отозван / нет такого
+		return ErrNotFound
+	}
+	return nil // ok
+}
+
+func (r *RefreshTokenGormRepository) RevokeAllForUser(ctx context.Context, userID uint) error { // отозвать все токены
+	return conn(ctx, r.db).Model(&types.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID). // активные токены пользователя
+		Update("revoked_at", time.Now()).Error               // UPDATE ... SET revoked_at=now
+}
@@ -0,0 +1,13 @@
+package repository // интерфейс репозитория refresh-токенов
+
+import (
+	"context"                            // ctx
+	"task-tracker/internal/domain/types" // модели
+)
+
+type RefreshTokenRepository interface { // контракт хранилища refresh-токенов
+	Create(ctx context.Context, token *types.RefreshToken) error             // сохранить
+	GetByHash(ctx context.Context, hash string) (*types.RefreshToken, error) // найти по хешу
+	Revoke(ctx context.Context, id uint) error                               // отозвать (ErrNotFound если уже отозван)
+	RevokeAllForUser(ctx context.Context, userID uint) error                 // отозвать все сессии пользователя
+}
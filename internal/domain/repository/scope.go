@@ -0,0 +1,6 @@
+package repository // пакет репозиториев
+
+type Scope struct { // чьи записи доступны вызывающему
+	UserID uint // владелец, которым ограничиваем выборку
+	All    bool // true = без ограничения (админ)
+}
@@ -1,12 +1,15 @@
 package repository // реализации репозиториев
 
 import (
-	"context" // ctx
-	"errors"  // errors.Is
+	"context"       // ctx
+	"encoding/json" // multi_select для @>
+	"errors"        // errors.Is
+	"time"          // сроки
 
 	"task-tracker/internal/domain/types" // модели
 
-	"gorm.io/gorm" // GORM
+	"gorm.io/gorm"        // GORM
+	"gorm.io/gorm/clause" // Associations
 )
 
 type TaskGormRepository struct { // repo на GORM
@@ -25,66 +28,726 @@ func (r *TaskGormRepository) Ping(ctx context.Context) error { // ping БД
 	return sqlDB.PingContext(ctx) // ping с ctx
 }
 
+func scopeTasks(q *gorm.DB, scope Scope) *gorm.DB { // ограничить задачи доступными вызывающему
+	if scope.All { // админ видит всё
+		return q
+	}
+	return q.Where( // свои личные + задачи пространств, где он участник
+		"(tasks.workspace_id IS NULL AND tasks.user_id = ?) OR tasks.workspace_id IN (SELECT workspace_id FROM workspace_members WHERE user_id = ?)",
+		scope.UserID, scope.UserID,
+	)
+}
+
+func preloadRelations(q *gorm.DB) *gorm.DB { // подгрузить метки, исполнителей, наблюдателей и пользовательские поля
+	byID := func(db *gorm.DB) *gorm.DB { return db.Order("users.id") }
+	return q.Preload("Labels", func(db *gorm.DB) *gorm.DB { return db.Order("labels.name, labels.id") }).
+		Preload("Assignees", byID).
+		Preload("Watchers", byID).
+		Preload("Fields", func(db *gorm.DB) *gorm.DB { return db.Order("field_id") }).
+		Preload("Fields.Field")
+}
+
+func filterLabels(q *gorm.DB, names []string, match string) *gorm.DB { // ?label=a&label=b
+	if len(names) == 0 { // без фильтра
+		return q
+	}
+	if match == types.LabelMatchAll { // все метки: задача набрала столько же разных имён
+		return q.Where(
+			"tasks.id IN (SELECT tl.task_id FROM task_labels tl JOIN labels l ON l.id = tl.label_id WHERE l.name IN ? GROUP BY tl.task_id HAVING COUNT(DISTINCT l.name) = ?)",
+			names, len(names),
+		)
+	}
+	return q.Where( // хотя бы одна
+		"tasks.id IN (SELECT tl.task_id FROM task_labels tl JOIN labels l ON l.id = tl.label_id WHERE l.name IN ?)",
+		names,
+	)
+}
+
 func (r *TaskGormRepository) Create(ctx context.Context, task *types.Task) error { // создать задачу
-	return r.db.WithContext(ctx).Create(task).Error // INSERT task
+	return conn(ctx, r.db).Omit("Workspace", "Parent", "Labels", "Watchers", "Checklist", "Fields", "Assignees.*").Create(task).Error // INSERT task + task_assignees
 }
 
-func (r *TaskGormRepository) List(ctx context.Context, done *bool, limit, offset int) ([]types.Task, error) { // список задач
+func (r *TaskGormRepository) List(ctx context.Context, scope Scope, filter types.TaskFilter, limit, offset int) ([]types.Task, error) { // список задач
 	var tasks []types.Task // результат
 
-	q := r.db.WithContext(ctx).Model(&types.Task{}).Order("id") // базовый запрос
-	if done != nil {                                            // фильтр done?
-		q = q.Where("done = ?", *done) // WHERE done=...
-	}
-	if limit > 0 { // лимит
+	q := preloadRelations(sortTasks(filterTasks(scopeTasks(conn(ctx, r.db).Model(&types.Task{}), scope), filter), filter.Sort)) // базовый запрос
+	if limit > 0 {                                                                                                              // лимит
 		q = q.Limit(limit) // LIMIT
 	}
 	if offset > 0 { // сдвиг
 		q = q.Offset(offset) // OFFSET
 	}
 
-	err := q.Find(&tasks).Error // выполнить SELECT
-	return tasks, err           // вернуть
+	if err := q.Find(&tasks).Error; err != nil { // выполнить SELECT
+		return nil, err
+	}
+	return tasks, r.enrich(ctx, tasks) // + прогресс, зависимости
+}
+
+func filterTasks(q *gorm.DB, filter types.TaskFilter) *gorm.DB { // условия TaskFilter (общие для List и Aggregate)
+	if filter.Done != nil { // фильтр done?
+		q = q.Where("tasks.done = ?", *filter.Done) // WHERE done=...
+	}
+	if filter.Status != nil { // фильтр по статусу?
+		q = q.Where("tasks.status = ?", *filter.Status) // WHERE status=...
+	}
+	if filter.WorkspaceID != nil { // фильтр по пространству?
+		q = q.Where("tasks.workspace_id = ?", *filter.WorkspaceID) // WHERE workspace_id=...
+	}
+	if filter.AssigneeID != nil { // очередь исполнителя
+		q = q.Where("tasks.id IN (SELECT task_id FROM task_assignees WHERE user_id = ?)", *filter.AssigneeID)
+	}
+	if filter.WatcherID != nil { // за чем следит пользователь
+		q = q.Where("tasks.id IN (SELECT task_id FROM task_watchers WHERE user_id = ?)", *filter.WatcherID)
+	}
+	if !filter.IncludeArchived { // архив — только по ?include_archived=true
+		q = q.Where("tasks.archived_at IS NULL")
+	}
+	for _, cond := range filter.FieldConds { // ?cf[key]=value
+		q = filterField(q, cond)
+	}
+	return filterLabels(q, filter.Labels, filter.LabelMatch) // фильтр по меткам
+}
+
+func filterField(q *gorm.DB, cond types.FieldCond) *gorm.DB { // значение пользовательского поля
+	const sub = "tasks.id IN (SELECT task_id FROM task_field_values WHERE field_id = ? AND "
+	switch cond.Field.Type {
+	case types.FieldTypeText: // без учёта регистра
+		return q.Where(sub+"LOWER(text_value) = LOWER(?))", cond.Field.ID, cond.Value)
+	case types.FieldTypeSelect:
+		return q.Where(sub+"text_value = ?)", cond.Field.ID, cond.Value)
+	case types.FieldTypeMultiSelect: // вариант среди выбранных
+		multi, _ := json.Marshal([]any{cond.Value})
+		return q.Where(sub+"multi_value @> ?::jsonb)", cond.Field.ID, string(multi))
+	case types.FieldTypeNumber:
+		return q.Where(sub+"number_value = ?)", cond.Field.ID, cond.Value)
+	case types.FieldTypeDate:
+		return q.Where(sub+"date_value = ?)", cond.Field.ID, cond.Value)
+	case types.FieldTypeUser:
+		return q.Where(sub+"user_value = ?)", cond.Field.ID, cond.Value)
+	}
+	return q
+}
+
+func sortTasks(q *gorm.DB, sort *types.TaskSort) *gorm.DB { // ?sort=...
+	if sort == nil { // по умолчанию — по id
+		return q.Order("tasks.id")
+	}
+	dir := "ASC"
+	if sort.Desc {
+		dir = "DESC"
+	}
+	if sort.Field == nil { // встроенная колонка (сервис проверил по белому списку)
+		return q.Order("tasks." + sort.Column + " " + dir + " NULLS LAST, tasks.id")
+	}
+
+	q = q.Joins("LEFT JOIN task_field_values sv ON sv.task_id = tasks.id AND sv.field_id = ?", sort.Field.ID)
+	col := "sv.text_value"
+	switch sort.Field.Type {
+	case types.FieldTypeText:
+		col = "LOWER(sv.text_value)"
+	case types.FieldTypeNumber:
+		col = "sv.number_value"
+	case types.FieldTypeDate:
+		col = "sv.date_value"
+	case types.FieldTypeUser: // по email исполнителя
+		q = q.Joins("LEFT JOIN users su ON su.id = sv.user_value")
+		col = "su.email"
+	}
+	return q.Order(col + " " + dir + " NULLS LAST, tasks.id") // задачи без значения — в конце
+}
+
+func (r *TaskGormRepository) Aggregate(ctx context.Context, scope Scope, filter types.TaskFilter) (*types.TaskAggregate, error) { // count + суммы оценок по выборке
+	q := filterTasks(scopeTasks(conn(ctx, r.db).Model(&types.Task{}), scope), filter)
+	var rows []struct { // по единицам оценок
+		Unit      string
+		Count     int64
+		Estimated int64
+		Total     float64
+		Remaining float64
+	}
+	err := q.Joins("LEFT JOIN workspaces ON workspaces.id = tasks.workspace_id").
+		Select(`COALESCE(workspaces.estimate_unit, ?) AS unit, COUNT(*) AS count, COUNT(tasks.estimate) AS estimated,
+			COALESCE(SUM(tasks.estimate), 0) AS total,
+			COALESCE(SUM(tasks.estimate) FILTER (WHERE NOT tasks.done), 0) AS remaining`, types.EstimateUnitPoints).
+		Group("unit").Order("unit").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	agg := &types.TaskAggregate{Estimates: []types.EstimateSum{}}
+	for _, row := range rows {
+		agg.Count += row.Count
+		if row.Estimated == 0 { // в этой единице ничего не оценено
+			continue
+		}
+		agg.Estimates = append(agg.Estimates, types.EstimateSum{Unit: row.Unit, Estimated: row.Estimated, Total: row.Total, Remaining: row.Remaining})
+	}
+	return agg, nil
 }
 
-func (r *TaskGormRepository) GetByID(ctx context.Context, id uint) (*types.Task, error) { // получить по id
-	var task types.Task                                 // объект
-	err := r.db.WithContext(ctx).First(&task, id).Error // SELECT ... WHERE id=?
-	if err != nil {                                     // обработка ошибок
+func (r *TaskGormRepository) AssignedEstimates(ctx context.Context, workspaceID uint, dueFrom, dueTo *time.Time) ([]types.AssignedEstimate, error) { // открытые задачи по исполнителям
+	q := conn(ctx, r.db).Table("task_assignees").
+		Joins("JOIN tasks ON tasks.id = task_assignees.task_id").
+		Where("tasks.workspace_id = ? AND NOT tasks.done AND tasks.deleted_at IS NULL", workspaceID)
+	if dueFrom != nil && dueTo != nil { // только задачи со сроком в периоде
+		q = q.Where("tasks.due_at >= ? AND tasks.due_at < ?", *dueFrom, *dueTo)
+	}
+	var rows []types.AssignedEstimate // у задачи с двумя исполнителями оценка идёт каждому
+	err := q.Select("task_assignees.user_id, COUNT(*) AS tasks, COALESCE(SUM(tasks.estimate), 0) AS estimate").
+		Group("task_assignees.user_id").
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *TaskGormRepository) GetByID(ctx context.Context, scope Scope, id uint) (*types.Task, error) { // получить по id
+	var task types.Task // объект
+	q := preloadRelations(scopeTasks(conn(ctx, r.db), scope)).
+		Preload("Checklist", func(db *gorm.DB) *gorm.DB { return db.Order("position, id") }) // чек-лист — только в карточке
+	err := q.First(&task, id).Error // SELECT ... WHERE id=? AND user_id=?
+	if err != nil {                 // обработка ошибок
 		if errors.Is(err, gorm.ErrRecordNotFound) { // нет записи
 			return nil, ErrNotFound // доменная not found
 		}
 		return nil, err // прочие ошибки
 	}
-	return &task, nil // вернуть задачу
+	one := []types.Task{task}                  // enrich работает со срезом
+	if err := r.enrich(ctx, one); err != nil { // прогресс, зависимости
+		return nil, err
+	}
+	return &one[0], nil // вернуть задачу
 }
 
-func (r *TaskGormRepository) Update(ctx context.Context, id uint, title *string, done *bool) (*types.Task, error) { // частичный апдейт
-	task, err := r.GetByID(ctx, id) // загрузить
+func (r *TaskGormRepository) Update(ctx context.Context, scope Scope, id, version uint, patch types.TaskPatch) (*types.Task, error) { // частичный апдейт
+	task, err := r.GetByID(ctx, scope, id) // загрузить (чужая = не найдена)
 	if err != nil {
 		return nil, err // ErrNotFound уже тут
 	}
+	if task.Version != version { // уже изменили после того, как вызывающий её прочитал
+		return nil, ErrStale
+	}
+
+	patch.Apply(task) // применить изменения
+	task.Version = version + 1
 
-	if title != nil { // менять title?
-		task.Title = *title
+	// UPDATE ... WHERE id = ? AND version = ?; Select("*") пишет и нулевые значения, как Save
+	res := conn(ctx, r.db).Model(task).Omit(clause.Associations).Select("*").Where("version = ?", version).Updates(task)
+	if res.Error != nil {
+		return nil, res.Error
 	}
-	if done != nil { // менять done?
-		task.Done = *done
+	if res.RowsAffected == 0 { // параллельная запись успела между чтением и UPDATE
+		return nil, ErrStale
 	}
+	return task, nil // вернуть
+}
+
+func (r *TaskGormRepository) Delete(ctx context.Context, scope Scope, id, version uint) error { // в корзину по id
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error { // задача и подзадачи вместе
+		res := scopeTasks(tx, scope).Where("tasks.version = ?", version).Delete(&types.Task{}, id) // UPDATE ... SET deleted_at (мягкое удаление)
+		if res.Error != nil {                                                                      // ошибка
+			return res.Error
+		}
+		if res.RowsAffected == 0 { // не удалилось
+			return notFoundOrStale(tx, scope, id)
+		}
+		return tx.Model(&types.Task{}).Where("parent_id = ?", id).
+			UpdateColumns(map[string]any{"parent_id": nil, "version": gorm.Expr("version + 1"), "updated_at": time.Now()}).Error // строка жива — FK SET NULL не сработает
+	})
+}
+
+const subtreeIDs = `WITH RECURSIVE sub AS (
+	SELECT id FROM tasks WHERE parent_id = ?
+	UNION
+	SELECT t.id FROM tasks t JOIN sub ON t.parent_id = sub.id
+) SELECT id FROM sub` // все потомки (UNION — не зациклится даже на битых данных)
 
-	if err := r.db.WithContext(ctx).Save(task).Error; err != nil { // сохранить
+func (r *TaskGormRepository) Children(ctx context.Context, scope Scope, parentID uint) ([]types.Task, error) { // прямые подзадачи
+	var tasks []types.Task
+	q := preloadRelations(scopeTasks(conn(ctx, r.db).Model(&types.Task{}), scope)).Where("parent_id = ?", parentID).Order("id")
+	if err := q.Find(&tasks).Error; err != nil {
 		return nil, err
 	}
-	return task, nil // вернуть
+	return tasks, r.enrich(ctx, tasks)
+}
+
+func (r *TaskGormRepository) Subtree(ctx context.Context, scope Scope, rootID uint) ([]types.Task, error) { // все потомки
+	var tasks []types.Task
+	q := preloadRelations(scopeTasks(conn(ctx, r.db).Model(&types.Task{}), scope)).Where("tasks.id IN ("+subtreeIDs+")", rootID).Order("id")
+	if err := q.Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	return tasks, r.enrich(ctx, tasks)
+}
+
+func (r *TaskGormRepository) AncestorIDs(ctx context.Context, id uint) ([]uint, error) { // сама задача + предки
+	var ids []uint
+	err := conn(ctx, r.db).Raw(`WITH RECURSIVE up AS (
+		SELECT id, parent_id FROM tasks WHERE id = ?
+		UNION
+		SELECT t.id, t.parent_id FROM tasks t JOIN up ON t.id = up.parent_id
+	) SELECT id FROM up`, id).Scan(&ids).Error
+	return ids, err
 }
 
-func (r *TaskGormRepository) Delete(ctx context.Context, id uint) error { // удалить по id
-	res := r.db.WithContext(ctx).Delete(&types.Task{}, id) // DELETE ... WHERE id=?
-	if res.Error != nil {                                  // ошибка
+func (r *TaskGormRepository) CountChildren(ctx context.Context, id uint) (int64, error) { // число прямых подзадач
+	var n int64
+	err := conn(ctx, r.db).Model(&types.Task{}).Where("parent_id = ?", id).Count(&n).Error
+	return n, err
+}
+
+func (r *TaskGormRepository) DeleteSubtree(ctx context.Context, scope Scope, id, version uint) error { // в корзину с потомками
+	now := time.Now() // одна метка на всё поддерево — по ней Restore вернёт его целиком
+
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error { // всё или ничего
+		if err := tx.Model(&types.Task{}).Where("id IN ("+subtreeIDs+")", id).UpdateColumn("deleted_at", now).Error; err != nil { // потомки
+			return err
+		}
+		res := scopeTasks(tx.Model(&types.Task{}), scope).Where("tasks.id = ? AND tasks.version = ?", id, version).UpdateColumn("deleted_at", now) // сама задача
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 { // не видна или изменена — откатываем и потомков
+			return notFoundOrStale(tx, scope, id)
+		}
+		return nil
+	})
+}
+
+func notFoundOrStale(tx *gorm.DB, scope Scope, id uint) error { // условная запись не прошла: задачи нет или версия ушла вперёд
+	var n int64
+	if err := scopeTasks(tx.Model(&types.Task{}), scope).Where("tasks.id = ?", id).Count(&n).Error; err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return ErrStale
+}
+
+func (r *TaskGormRepository) Trash(ctx context.Context, scope Scope, workspaceID *uint, limit, offset int) ([]types.Task, error) { // корзина
+	q := scopeTasks(conn(ctx, r.db).Unscoped().Model(&types.Task{}), scope).Where("tasks.deleted_at IS NOT NULL")
+	if workspaceID != nil {
+		q = q.Where("tasks.workspace_id = ?", *workspaceID)
+	}
+	var tasks []types.Task
+	if err := preloadRelations(q).Order("tasks.deleted_at DESC, tasks.id").Limit(limit).Offset(offset).Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	return tasks, r.enrich(ctx, tasks)
+}
+
+func (r *TaskGormRepository) GetDeleted(ctx context.Context, scope Scope, id uint) (*types.Task, error) { // задача из корзины
+	var task types.Task
+	err := preloadRelations(scopeTasks(conn(ctx, r.db).Unscoped().Model(&types.Task{}), scope)).
+		Preload("Checklist", func(db *gorm.DB) *gorm.DB { return db.Order("position, id") }). // как в карточке — история сравнивает с ней
+		Where("tasks.deleted_at IS NOT NULL").First(&task, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	one := []types.Task{task} // enrich работает со срезом
+	if err := r.enrich(ctx, one); err != nil {
+		return nil, err
+	}
+	return &one[0], nil
+}
+
+func (r *TaskGormRepository) Restore(ctx context.Context, id uint) ([]uint, error) { // вернуть из корзины
+	var descendants []uint
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error { // задача и её поддерево вместе
+		var task types.Task
+		err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "deleted_at").
+			Where("deleted_at IS NOT NULL").First(&task, id).Error // параллельный restore/purge ждёт нас
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNotFound
+			}
+			return err
+		}
+		err = tx.Unscoped().Model(&types.Task{}).
+			Where("id IN ("+subtreeIDs+") AND deleted_at = ?", id, task.DeletedAt.Time). // потомки, удалённые той же операцией
+			Order("id").Pluck("id", &descendants).Error
+		if err != nil {
+			return err
+		}
+		return tx.Unscoped().Model(&types.Task{}).
+			Where("id IN ?", append([]uint{id}, descendants...)).
+			UpdateColumns(map[string]any{"deleted_at": nil, "version": gorm.Expr("version + 1"), "updated_at": time.Now()}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return descendants, nil
+}
+
+func (r *TaskGormRepository) Purge(ctx context.Context, before time.Time) (int64, []string, error) { // стереть навсегда
+	var (
+		n    int64
+		keys []string
+	)
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error { // ключи и удаление по одному снимку
+		err := tx.Model(&types.Attachment{}).
+			Where("task_id IN (SELECT id FROM tasks WHERE deleted_at < ?)", before).
+			Pluck("storage_key", &keys).Error // строки вложений уйдут каскадом, объекты — нет
+		if err != nil {
+			return err
+		}
+		res := tx.Unscoped().Where("deleted_at < ?", before).Delete(&types.Task{}) // связи уходят по FK CASCADE
+		n = res.RowsAffected
 		return res.Error
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	return n, keys, nil
+}
+
+func (r *TaskGormRepository) Touch(ctx context.Context, ids ...uint) error { // новая версия без изменения колонок
+	return conn(ctx, r.db).Model(&types.Task{}).Where("id IN ?", ids).
+		UpdateColumns(map[string]any{"version": gorm.Expr("version + 1"), "updated_at": time.Now()}).Error
+}
+
+func (r *TaskGormRepository) LastModified(ctx context.Context, scope Scope, workspaceID *uint) (time.Time, error) { // max(updated_at, deleted_at), включая корзину
+	q := scopeTasks(conn(ctx, r.db).Unscoped().Model(&types.Task{}), scope) // удалённые тоже: их уход меняет списки
+	if workspaceID != nil {
+		q = q.Where("tasks.workspace_id = ?", *workspaceID)
 	}
-	if res.RowsAffected == 0 { // не удалилось
+	var last *time.Time
+	err := q.Select("MAX(GREATEST(tasks.updated_at, tasks.deleted_at))").Scan(&last).Error // GREATEST в Postgres пропускает NULL
+	if err != nil || last == nil {
+		return time.Time{}, err
+	}
+	return *last, nil
+}
+
+func (r *TaskGormRepository) SetArchived(ctx context.Context, scope Scope, id uint, at *time.Time) error { // archived_at = at
+	res := scopeTasks(conn(ctx, r.db).Model(&types.Task{}), scope).Where("tasks.id = ?", id).
+		Updates(map[string]any{"archived_at": at, "version": gorm.Expr("version + 1")})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 { // не видна
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *TaskGormRepository) ArchiveDone(ctx context.Context, scope Scope, workspaceID *uint, before time.Time) ([]uint, error) { // массовая архивация
+	var ids []uint
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error { // выборка и архивация по одному снимку
+		q := scopeTasks(tx.Model(&types.Task{}), scope).
+			Where("tasks.done AND tasks.archived_at IS NULL AND COALESCE(tasks.completed_at, tasks.updated_at) < ?", before) // старые без completed_at — по updated_at
+		switch {
+		case workspaceID != nil: // одно пространство
+			q = q.Where("tasks.workspace_id = ?", *workspaceID)
+		case !scope.All: // только свои личные
+			q = q.Where("tasks.workspace_id IS NULL AND tasks.user_id = ?", scope.UserID)
+		}
+		if err := q.Clauses(clause.Locking{Strength: "UPDATE"}).Order("tasks.id").Pluck("tasks.id", &ids).Error; err != nil { // id нужны для истории
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		return tx.Model(&types.Task{}).Where("id IN ?", ids).
+			Updates(map[string]any{"archived_at": time.Now(), "version": gorm.Expr("version + 1")}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *TaskGormRepository) CreateOccurrence(ctx context.Context, prevID uint, next *types.Task) error { // следующее вхождение серии
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error { // вставка и ссылка вместе
+		var prev types.Task
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "next_occurrence_id").First(&prev, prevID).Error // параллельное закрытие ждёт нас
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNotFound
+			}
+			return err
+		}
+		if prev.NextOccurrenceID != nil { // уже создано
+			return ErrDuplicate
+		}
+		if err := tx.Omit("Workspace", "Parent", "Fields", "Labels.*", "Assignees.*", "Watchers.*").Create(next).Error; err != nil { // задача + связи + чек-лист
+			return err
+		}
+		if len(next.Fields) > 0 { // значения пользовательских полей
+			for i := range next.Fields {
+				next.Fields[i].TaskID = next.ID
+			}
+			if err := tx.Omit(clause.Associations).Create(&next.Fields).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&types.Task{}).Where("id = ?", prevID).
+			Updates(map[string]any{"next_occurrence_id": next.ID, "version": gorm.Expr("version + 1")}).Error
+	})
+}
+
+func (r *TaskGormRepository) enrich(ctx context.Context, tasks []types.Task) error { // вычисляемые поля задач
+	if err := r.fillProgress(ctx, tasks); err != nil {
+		return err
+	}
+	if err := r.fillDependencies(ctx, tasks); err != nil {
+		return err
+	}
+	if err := r.fillCommentCounts(ctx, tasks); err != nil {
+		return err
+	}
+	if err := r.fillChecklistProgress(ctx, tasks); err != nil {
+		return err
+	}
+	return r.fillTrackedTime(ctx, tasks)
+}
+
+func (r *TaskGormRepository) fillProgress(ctx context.Context, tasks []types.Task) error { // n из m подзадач для каждой задачи
+	if len(tasks) == 0 {
+		return nil
+	}
+	ids := make([]uint, 0, len(tasks))
+	for i := range tasks {
+		ids = append(ids, tasks[i].ID)
+	}
+
+	var rows []struct { // агрегат по родителю
+		ParentID uint
+		Total    int
+		Done     int
+	}
+	err := conn(ctx, r.db).Model(&types.Task{}).
+		Select("parent_id, COUNT(*) AS total, COUNT(*) FILTER (WHERE done) AS done").
+		Where("parent_id IN ?", ids).
+		Group("parent_id").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	byParent := make(map[uint]*types.TaskProgress, len(rows))
+	for _, row := range rows {
+		byParent[row.ParentID] = &types.TaskProgress{Done: row.Done, Total: row.Total}
+	}
+	for i := range tasks { // без подзадач — nil
+		tasks[i].Progress = byParent[tasks[i].ID]
+	}
+	return nil
+}
+
+func (r *TaskGormRepository) fillDependencies(ctx context.Context, tasks []types.Task) error { // blocked_by/blocking пачкой
+	if len(tasks) == 0 {
+		return nil
+	}
+	ids := make([]uint, 0, len(tasks))
+	for i := range tasks {
+		ids = append(ids, tasks[i].ID)
+	}
+
+	type refRow struct { // ссылка + задача, к которой она относится
+		OwnerID uint
+		types.TaskRef
+	}
+	var blockedBy, blocking []refRow
+	err := conn(ctx, r.db).Table("task_dependencies d").
+		Select("d.task_id AS owner_id, t.id, t.title, t.status, t.done").
+		Joins("JOIN tasks t ON t.id = d.blocked_by_id AND t.deleted_at IS NULL").
+		Where("d.task_id IN ?", ids).
+		Order("t.id").
+		Scan(&blockedBy).Error
+	if err != nil {
+		return err
+	}
+	err = conn(ctx, r.db).Table("task_dependencies d").
+		Select("d.blocked_by_id AS owner_id, t.id, t.title, t.status, t.done").
+		Joins("JOIN tasks t ON t.id = d.task_id AND t.deleted_at IS NULL").
+		Where("d.blocked_by_id IN ?", ids).
+		Order("t.id").
+		Scan(&blocking).Error
+	if err != nil {
+		return err
+	}
+
+	byTask := make(map[uint]*types.Task, len(tasks))
+	for i := range tasks {
+		byTask[tasks[i].ID] = &tasks[i]
+	}
+	for _, row := range blockedBy {
+		t := byTask[row.OwnerID]
+		t.BlockedBy = append(t.BlockedBy, row.TaskRef)
+	}
+	for _, row := range blocking {
+		t := byTask[row.OwnerID]
+		t.Blocking = append(t.Blocking, row.TaskRef)
+	}
+	return nil
+}
+
+func (r *TaskGormRepository) AddDependency(ctx context.Context, taskID, blockedByID uint) error { // добавить ребро
+	return conn(ctx, r.db).Exec( // уже есть — не ошибка
+		"INSERT INTO task_dependencies (task_id, blocked_by_id, created_at) VALUES (?, ?, NOW()) ON CONFLICT DO NOTHING", taskID, blockedByID,
+	).Error
+}
+
+func (r *TaskGormRepository) RemoveDependency(ctx context.Context, taskID, blockedByID uint) error { // убрать ребро
+	res := conn(ctx, r.db).Where("task_id = ? AND blocked_by_id = ?", taskID, blockedByID).Delete(&types.TaskDependency{})
+	if res.Error != nil { // ошибка
+		return res.Error
+	}
+	if res.RowsAffected == 0 { // и не было
+		return ErrNotFound
+	}
+	return nil // ok
+}
+
+func (r *TaskGormRepository) BlockerIDs(ctx context.Context, id uint) ([]uint, error) { // от чего задача зависит транзитивно
+	var ids []uint
+	err := conn(ctx, r.db).Raw(`WITH RECURSIVE dep AS (
+		SELECT blocked_by_id AS id FROM task_dependencies WHERE task_id = ?
+		UNION
+		SELECT d.blocked_by_id FROM task_dependencies d JOIN dep ON d.task_id = dep.id
+	) SELECT id FROM dep`, id).Scan(&ids).Error
+	return ids, err
+}
+
+func (r *TaskGormRepository) UnfinishedBlockers(ctx context.Context, id uint) ([]uint, error) { // незакрытые прямые блокеры
+	var ids []uint
+	err := conn(ctx, r.db).Raw(
+		"SELECT t.id FROM task_dependencies d JOIN tasks t ON t.id = d.blocked_by_id WHERE d.task_id = ? AND NOT t.done AND t.deleted_at IS NULL ORDER BY t.id", id,
+	).Scan(&ids).Error
+	return ids, err
+}
+
+func (r *TaskGormRepository) addUserLink(ctx context.Context, table string, taskID, userID uint) error { // task_assignees / task_watchers
+	return conn(ctx, r.db).Exec( // уже есть — не ошибка
+		"INSERT INTO "+table+" (task_id, user_id) VALUES (?, ?) ON CONFLICT DO NOTHING", taskID, userID,
+	).Error
+}
+
+func (r *TaskGormRepository) removeUserLink(ctx context.Context, table string, taskID, userID uint) error { // убрать связь
+	res := conn(ctx, r.db).Exec("DELETE FROM "+table+" WHERE task_id = ? AND user_id = ?", taskID, userID)
+	if res.Error != nil { // ошибка
+		return res.Error
+	}
+	if res.RowsAffected == 0 { // и не было
 		return ErrNotFound
 	}
 	return nil // ok
 }
+
+func (r *TaskGormRepository) AddAssignee(ctx context.Context, taskID, userID uint) error { // назначить
+	return r.addUserLink(ctx, "task_assignees", taskID, userID)
+}
+
+func (r *TaskGormRepository) RemoveAssignee(ctx context.Context, taskID, userID uint) error { // снять исполнителя
+	return r.removeUserLink(ctx, "task_assignees", taskID, userID)
+}
+
+func (r *TaskGormRepository) AddWatcher(ctx context.Context, taskID, userID uint) error { // подписать
+	return r.addUserLink(ctx, "task_watchers", taskID, userID)
+}
+
+func (r *TaskGormRepository) RemoveWatcher(ctx context.Context, taskID, userID uint) error { // отписать
+	return r.removeUserLink(ctx, "task_watchers", taskID, userID)
+}
+
+func (r *TaskGormRepository) fillCommentCounts(ctx context.Context, tasks []types.Task) error { // comment_count пачкой
+	if len(tasks) == 0 {
+		return nil
+	}
+	ids := make([]uint, 0, len(tasks))
+	for i := range tasks {
+		ids = append(ids, tasks[i].ID)
+	}
+
+	var rows []struct { // агрегат по задаче
+		TaskID uint
+		Count  int
+	}
+	err := conn(ctx, r.db).Model(&types.Comment{}).
+		Select("task_id, COUNT(*) AS count").
+		Where("task_id IN ? AND deleted_at IS NULL", ids).
+		Group("task_id").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	byTask := make(map[uint]int, len(rows))
+	for _, row := range rows {
+		byTask[row.TaskID] = row.Count
+	}
+	for i := range tasks {
+		tasks[i].CommentCount = byTask[tasks[i].ID]
+	}
+	return nil
+}
+
+func (r *TaskGormRepository) fillChecklistProgress(ctx context.Context, tasks []types.Task) error { // отмечено n из m пунктов
+	if len(tasks) == 0 {
+		return nil
+	}
+	ids := make([]uint, 0, len(tasks))
+	for i := range tasks {
+		ids = append(ids, tasks[i].ID)
+	}
+
+	var rows []struct { // агрегат по задаче
+		TaskID uint
+		Total  int
+		Done   int
+	}
+	err := conn(ctx, r.db).Model(&types.ChecklistItem{}).
+		Select("task_id, COUNT(*) AS total, COUNT(*) FILTER (WHERE done) AS done").
+		Where("task_id IN ?", ids).
+		Group("task_id").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	byTask := make(map[uint]*types.TaskProgress, len(rows))
+	for _, row := range rows {
+		byTask[row.TaskID] = &types.TaskProgress{Done: row.Done, Total: row.Total}
+	}
+	for i := range tasks { // без чек-листа — nil
+		tasks[i].ChecklistProgress = byTask[tasks[i].ID]
+	}
+	return nil
+}
+
+func (r *TaskGormRepository) fillTrackedTime(ctx context.Context, tasks []types.Task) error { // tracked_seconds пачкой
+	if len(tasks) == 0 {
+		return nil
+	}
+	ids := make([]uint, 0, len(tasks))
+	for i := range tasks {
+		ids = append(ids, tasks[i].ID)
+	}
+
+	var rows []struct { // агрегат по задаче
+		TaskID  uint
+		Seconds int64
+	}
+	err := conn(ctx, r.db).Model(&types.TimeEntry{}).
+		Select("task_id, SUM(seconds) AS seconds").
+		Where("task_id IN ? AND ended_at IS NOT NULL", ids).
+		Group("task_id").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	byTask := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		byTask[row.TaskID] = row.Seconds
+	}
+	for i := range tasks {
+		tasks[i].TrackedSeconds = byTask[tasks[i].ID]
+	}
+	return nil
+}
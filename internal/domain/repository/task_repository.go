@@ -1,7 +1,9 @@
 package repository // интерфейс репозитория
 
 import (
-	"context"                            // ctx
+	"context" // ctx
+	"time"    // сроки
+
 	"task-tracker/internal/domain/types" // модели
 )
 
@@ -9,9 +11,41 @@ type TaskRepository interface { // контракт хранилища
 	Ping(ctx context.Context) error // проверка БД
 
 	Create(ctx context.Context, task *types.Task) error // создать
-	List(ctx context.Context, done *bool, limit, offset int) ([]types.Task, error)
-	GetByID(ctx context.Context, id uint) (*types.Task, error) // получить
+	List(ctx context.Context, scope Scope, filter types.TaskFilter, limit, offset int) ([]types.Task, error)
+	GetByID(ctx context.Context, scope Scope, id uint) (*types.Task, error) // получить
+
+	Update(ctx context.Context, scope Scope, id, version uint, patch types.TaskPatch) (*types.Task, error) // обновить частично, если версия ещё version (иначе ErrStale)
+	Delete(ctx context.Context, scope Scope, id, version uint) error                                       // в корзину, если версия ещё version (подзадачи всплывают наверх; иначе ErrStale)
+
+	Children(ctx context.Context, scope Scope, parentID uint) ([]types.Task, error) // прямые подзадачи
+	Subtree(ctx context.Context, scope Scope, rootID uint) ([]types.Task, error)    // все потомки (плоско, с ParentID)
+	AncestorIDs(ctx context.Context, id uint) ([]uint, error)                       // id и цепочка родителей вверх
+	CountChildren(ctx context.Context, id uint) (int64, error)                      // сколько прямых подзадач
+	DeleteSubtree(ctx context.Context, scope Scope, id, version uint) error         // в корзину вместе с потомками, если версия ещё version (иначе ErrStale)
+
+	Trash(ctx context.Context, scope Scope, workspaceID *uint, limit, offset int) ([]types.Task, error) // задачи в корзине (свежие первыми)
+	GetDeleted(ctx context.Context, scope Scope, id uint) (*types.Task, error)                          // задача из корзины
+	Restore(ctx context.Context, id uint) ([]uint, error)                                               // вернуть вместе с удалёнными заодно потомками (их id)
+	Purge(ctx context.Context, before time.Time) (int64, []string, error)                               // стереть навсегда удалённые раньше before (+ ключи их вложений в BlobStore)
+
+	Touch(ctx context.Context, ids ...uint) error                                        // version+1 и updated_at после изменения связей и производных полей (метки, исполнители, чек-лист, комментарии, время)
+	LastModified(ctx context.Context, scope Scope, workspaceID *uint) (time.Time, error) // последнее изменение/удаление видимых задач (Last-Modified списков)
+
+	SetArchived(ctx context.Context, scope Scope, id uint, at *time.Time) error                        // в архив / из архива (nil)
+	ArchiveDone(ctx context.Context, scope Scope, workspaceID *uint, before time.Time) ([]uint, error) // в архив выполненные раньше before (их id)
+
+	Aggregate(ctx context.Context, scope Scope, filter types.TaskFilter) (*types.TaskAggregate, error)                    // count и суммы оценок по всей выборке
+	AssignedEstimates(ctx context.Context, workspaceID uint, dueFrom, dueTo *time.Time) ([]types.AssignedEstimate, error) // нагрузка исполнителей (открытые задачи)
+
+	CreateOccurrence(ctx context.Context, prevID uint, next *types.Task) error // создать следующее вхождение (ErrDuplicate — уже создано)
+
+	AddDependency(ctx context.Context, taskID, blockedByID uint) error    // добавить ребро (идемпотентно)
+	RemoveDependency(ctx context.Context, taskID, blockedByID uint) error // убрать ребро (ErrNotFound — не было)
+	BlockerIDs(ctx context.Context, id uint) ([]uint, error)              // все блокеры транзитивно
+	UnfinishedBlockers(ctx context.Context, id uint) ([]uint, error)      // прямые блокеры, ещё не выполненные
 
-	Update(ctx context.Context, id uint, title *string, done *bool) (*types.Task, error) // обновить частично
-	Delete(ctx context.Context, id uint) error                                           // удалить
+	AddAssignee(ctx context.Context, taskID, userID uint) error    // назначить (идемпотентно)
+	RemoveAssignee(ctx context.Context, taskID, userID uint) error // снять (ErrNotFound — не был назначен)
+	AddWatcher(ctx context.Context, taskID, userID uint) error     // подписать (идемпотентно)
+	RemoveWatcher(ctx context.Context, taskID, userID uint) error  // отписать (ErrNotFound — не следил)
 }
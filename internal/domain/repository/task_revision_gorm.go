@@ -0,0 +1,41 @@
+package repository // реализации репозиториев
+
+import (
+	"context" // ctx
+	"errors"  // errors.Is
+
+	"task-tracker/internal/domain/types" // модели
+
+	"gorm.io/gorm" // GORM
+)
+
+type TaskRevisionGormRepository struct { // repo истории задач на GORM
+	db *gorm.DB // подключение
+}
+
+func NewTaskRevisionGormRepository(db *gorm.DB) *TaskRevisionGormRepository { // конструктор
+	return &TaskRevisionGormRepository{db: db} // сохранить db
+}
+
+func (r *TaskRevisionGormRepository) Create(ctx context.Context, rev *types.TaskRevision) error { // INSERT task_revision
+	return conn(ctx, r.db).Omit("Task", "User").Create(rev).Error
+}
+
+func (r *TaskRevisionGormRepository) ListByTask(ctx context.Context, taskID uint, limit, offset int) ([]types.TaskRevision, error) { // история задачи
+	var list []types.TaskRevision
+	err := conn(ctx, r.db).Preload("User").Where("task_id = ?", taskID).
+		Order("id DESC").Limit(limit).Offset(offset).Find(&list).Error
+	return list, err
+}
+
+func (r *TaskRevisionGormRepository) GetByID(ctx context.Context, taskID, id uint) (*types.TaskRevision, error) { // получить по id
+	var rev types.TaskRevision
+	err := conn(ctx, r.db).Preload("User").Where("task_id = ?", taskID).First(&rev, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) { // нет записи
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &rev, nil
+}
@@ -0,0 +1,13 @@
+package repository // интерфейс репозитория истории задач
+
+import (
+	"context" // ctx
+
+	"task-tracker/internal/domain/types" // модели
+)
+
+type TaskRevisionRepository interface { // контракт хранилища ревизий
+	Create(ctx context.Context, rev *types.TaskRevision) error                                    // записать ревизию
+	ListByTask(ctx context.Context, taskID uint, limit, offset int) ([]types.TaskRevision, error) // история задачи (свежие первыми, с User)
+	GetByID(ctx context.Context, taskID, id uint) (*types.TaskRevision, error)                    // одна ревизия задачи
+}
@@ -0,0 +1,131 @@
+package repository // реализации репозиториев
+
+import (
+	"context" // ctx
+	"errors"  // errors.Is
+	"time"    // ended_at
+
+	"task-tracker/internal/domain/types" // модели
+
+	"gorm.io/gorm" // GORM
+)
+
+type TimeEntryGormRepository struct { // repo учёта времени на GORM
+	db *gorm.DB // подключение
+}
+
+func NewTimeEntryGormRepository(db *gorm.DB) *TimeEntryGormRepository { // конструктор
+	return &TimeEntryGormRepository{db: db} // сохранить db
+}
+
+func (r *TimeEntryGormRepository) Create(ctx context.Context, e *types.TimeEntry) error { // создать запись / запустить таймер
+	err := conn(ctx, r.db).Omit("Task", "User").Create(e).Error // INSERT time_entry
+	if errors.Is(err, gorm.ErrDuplicatedKey) {                  // idx_time_entries_running
+		return ErrDuplicate
+	}
+	return err
+}
+
+func (r *TimeEntryGormRepository) ListByTask(ctx context.Context, taskID uint) ([]types.TimeEntry, error) { // записи задачи
+	var list []types.TimeEntry
+	err := conn(ctx, r.db).Preload("User").Where("task_id = ?", taskID).Order("started_at, id").Find(&list).Error
+	return list, err
+}
+
+func (r *TimeEntryGormRepository) GetByID(ctx context.Context, taskID, id uint) (*types.TimeEntry, error) { // получить по id
+	var e types.TimeEntry
+	err := conn(ctx, r.db).Preload("User").Where("task_id = ?", taskID).First(&e, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) { // нет записи
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *TimeEntryGormRepository) Running(ctx context.Context, userID uint) (*types.TimeEntry, error) { // идущий таймер
+	var e types.TimeEntry
+	err := conn(ctx, r.db).Preload("User").Where("user_id = ? AND ended_at IS NULL", userID).First(&e).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) { // таймер не запущен
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *TimeEntryGormRepository) Stop(ctx context.Context, id uint, endedAt time.Time) (*types.TimeEntry, error) { // остановить таймер
+	var e types.TimeEntry
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error { // перечитать и закрыть атомарно
+		if err := tx.Preload("User").Where("ended_at IS NULL").First(&e, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) { // уже остановлен
+				return ErrNotFound
+			}
+			return err
+		}
+		e.EndedAt = &endedAt
+		e.Seconds = max(int64(endedAt.Sub(e.StartedAt)/time.Second), 0)
+		return tx.Model(&e).Where("ended_at IS NULL").Updates(map[string]any{"ended_at": e.EndedAt, "seconds": e.Seconds}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *TimeEntryGormRepository) Delete(ctx context.Context, id uint) error { // удалить запись
+	res := conn(ctx, r.db).Delete(&types.TimeEntry{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *TimeEntryGormRepository) Report(ctx context.Context, scope Scope, filter types.TimeReportFilter) (*types.TimeReport, error) { // агрегаты за период
+	base := func() *gorm.DB { // завершённые записи видимых задач за период
+		q := scopeTasks(conn(ctx, r.db).Table("time_entries AS e").Joins("JOIN tasks ON tasks.id = e.task_id AND tasks.deleted_at IS NULL"), scope).
+			Where("e.ended_at IS NOT NULL AND e.started_at >= ? AND e.started_at < ?", filter.From, filter.To)
+		if filter.WorkspaceID != nil {
+			q = q.Where("tasks.workspace_id = ?", *filter.WorkspaceID)
+		}
+		if filter.UserID != nil {
+			q = q.Where("e.user_id = ?", *filter.UserID)
+		}
+		return q
+	}
+
+	report := &types.TimeReport{ // пустые срезы — пустые массивы в JSON
+		ByUser:  []types.TimeByUser{},
+		ByLabel: []types.TimeByLabel{},
+		ByDay:   []types.TimeByDay{},
+	}
+	if err := base().Select("COALESCE(SUM(e.seconds), 0)").Row().Scan(&report.TotalSeconds); err != nil {
+		return nil, err
+	}
+	err := base().Joins("JOIN users ON users.id = e.user_id").
+		Select("e.user_id, users.email, SUM(e.seconds) AS seconds").
+		Group("e.user_id, users.email").Order("seconds DESC, e.user_id").
+		Scan(&report.ByUser).Error
+	if err != nil {
+		return nil, err
+	}
+	err = base().Joins("JOIN task_labels ON task_labels.task_id = tasks.id").Joins("JOIN labels ON labels.id = task_labels.label_id").
+		Select("labels.id AS label_id, labels.name, SUM(e.seconds) AS seconds").
+		Group("labels.id, labels.name").Order("seconds DESC, labels.id").
+		Scan(&report.ByLabel).Error
+	if err != nil {
+		return nil, err
+	}
+	err = base().Select("date_trunc('day', e.started_at AT TIME ZONE 'UTC') AS day, SUM(e.seconds) AS seconds").
+		Group("day").Order("day").
+		Scan(&report.ByDay).Error
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
@@ -0,0 +1,18 @@
+package repository // интерфейс репозитория учёта времени
+
+import (
+	"context" // ctx
+	"time"    // ended_at
+
+	"task-tracker/internal/domain/types" // модели
+)
+
+type TimeEntryRepository interface { // контракт хранилища учёта времени
+	Create(ctx context.Context, e *types.TimeEntry) error                                              // создать (ErrDuplicate — у пользователя уже идёт таймер)
+	ListByTask(ctx context.Context, taskID uint) ([]types.TimeEntry, error)                            // записи задачи (с User)
+	GetByID(ctx context.Context, taskID, id uint) (*types.TimeEntry, error)                            // получить
+	Running(ctx context.Context, userID uint) (*types.TimeEntry, error)                                // запущенный таймер пользователя (ErrNotFound — нет)
+	Stop(ctx context.Context, id uint, endedAt time.Time) (*types.TimeEntry, error)                    // остановить таймер
+	Delete(ctx context.Context, id uint) error                                                         // удалить
+	Report(ctx context.Context, scope Scope, filter types.TimeReportFilter) (*types.TimeReport, error) // агрегаты по видимым задачам
+}
@@ -0,0 +1,30 @@
+package repository // реализации репозиториев
+
+import (
+	"context" // ctx
+
+	"gorm.io/gorm" // GORM
+)
+
+type txKey struct{} // ключ транзакции в ctx
+
+type GormTransactor struct { // Transactor на GORM
+	db *gorm.DB // подключение
+}
+
+func NewGormTransactor(db *gorm.DB) *GormTransactor { // конструктор
+	return &GormTransactor{db: db}
+}
+
+func (t *GormTransactor) Transaction(ctx context.Context, fn func(ctx context.Context) error) error { // BEGIN ... COMMIT/ROLLBACK (вложенная — SAVEPOINT)
+	return conn(ctx, t.db).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx)) // репозитории подхватят tx из ctx
+	})
+}
+
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB { // транзакция из ctx, если она есть, иначе обычное подключение
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
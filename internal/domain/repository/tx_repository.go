@@ -0,0 +1,7 @@
+package repository // пакет репозиториев
+
+import "context" // ctx
+
+type Transactor interface { // несколько вызовов repo в одной транзакции
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error // fn с ctx транзакции; ошибка = откат
+}
@@ -0,0 +1,155 @@
+package repository // реализации репозиториев
+
+import (
+	"context" // ctx
+	"errors"  // errors.Is
+	"time"    // updated_at задач
+
+	"task-tracker/internal/domain/types" // модели
+
+	"gorm.io/gorm" // GORM
+)
+
+type UserGormRepository struct { // repo пользователей на GORM
+	db *gorm.DB // подключение
+}
+
+func NewUserGormRepository(db *gorm.DB) *UserGormRepository { // конструктор
+	return &UserGormRepository{db: db} // сохранить db
+}
+
+func (r *UserGormRepository) Create(ctx context.Context, user *types.User) error { // создать пользователя
+	err := conn(ctx, r.db).Create(user).Error  // INSERT user
+	if errors.Is(err, gorm.ErrDuplicatedKey) { // email занят
+		return ErrDuplicate
+	}
+	return err // ok / прочее
+}
+
+func (r *UserGormRepository) List(ctx context.Context, limit, offset int) ([]types.User, error) { // список пользователей
+	var users []types.User // результат
+
+	q := conn(ctx, r.db).Model(&types.User{}).Order("id") // базовый запрос
+	if limit > 0 {                                        // лимит
+		q = q.Limit(limit) // LIMIT
+	}
+	if offset > 0 { // сдвиг
+		q = q.Offset(offset) // OFFSET
+	}
+
+	err := q.Find(&users).Error // выполнить SELECT
+	return users, err           // вернуть
+}
+
+func (r *UserGormRepository) GetByID(ctx context.Context, id uint) (*types.User, error) { // получить по id
+	var user types.User                           // объект
+	err := conn(ctx, r.db).First(&user, id).Error // SELECT ... WHERE id=?
+	if err != nil {                               // обработка ошибок
+		if errors.Is(err, gorm.ErrRecordNotFound) { // нет записи
+			return nil, ErrNotFound // доменная not found
+		}
+		return nil, err // прочие ошибки
+	}
+	return &user, nil // вернуть пользователя
+}
+
+func (r *UserGormRepository) GetByEmail(ctx context.Context, email string) (*types.User, error) { // получить по email
+	var user types.User                                                 // объект
+	err := conn(ctx, r.db).Where("email = ?", email).First(&user).Error // SELECT ... WHERE email=?
+	if err != nil {                                                     // обработка ошибок
+		if errors.Is(err, gorm.ErrRecordNotFound) { // нет записи
+			return nil, ErrNotFound // доменная not found
+		}
+		return nil, err // прочие ошибки
+	}
+	return &user, nil // вернуть пользователя
+}
+
+func (r *UserGormRepository) Exists(ctx context.Context, id uint) (bool, error) { // есть ли пользователь
+	var count int64                                                                     // счётчик
+	err := conn(ctx, r.db).Model(&types.User{}).Where("id = ?", id).Count(&count).Error // SELECT count(*)
+	return count > 0, err                                                               // вернуть
+}
+
+func (r *UserGormRepository) Update(ctx context.Context, id uint, email, role *string) (*types.User, error) { // частичный апдейт
+	user, err := r.GetByID(ctx, id) // загрузить
+	if err != nil {
+		return nil, err // ErrNotFound уже тут
+	}
+
+	if email != nil { // менять email?
+		user.Email = *email
+	}
+	if role != nil { // менять роль?
+		user.Role = *role
+	}
+
+	err = conn(ctx, r.db).Transaction(func(tx *gorm.DB) error { // пользователь и карточки задач с его email — вместе
+		if err := tx.Save(user).Error; err != nil { // сохранить
+			return err
+		}
+		if email == nil {
+			return nil
+		}
+		return touchUserTasks(tx, userShownTasks, id)
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) { // email занят
+			return nil, ErrDuplicate
+		}
+		return nil, err
+	}
+	return user, nil // вернуть
+}
+
+func (r *UserGormRepository) Delete(ctx context.Context, id uint) ([]string, error) { // удалить по id
+	var keys []string
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error { // ключи и удаление по одному снимку
+		err := tx.Model(&types.Attachment{}).Where("uploader_id = ?", id).
+			Pluck("storage_key", &keys).Error // его вложения уйдут каскадом, объекты — нет
+		if err != nil {
+			return err
+		}
+		if err := touchUserTasks(tx, userDataTasks, id); err != nil { // пока строки, уходящие каскадом, на месте
+			return err
+		}
+		res := tx.Delete(&types.User{}, id) // DELETE ... WHERE id=?
+		if res.Error != nil {               // ошибка
+			if errors.Is(res.Error, gorm.ErrForeignKeyViolated) { // есть задачи пользователя
+				return ErrReferenced
+			}
+			return res.Error
+		}
+		if res.RowsAffected == 0 { // не удалилось
+			return ErrNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil // ok
+}
+
+const (
+	userShownTasks = `SELECT task_id FROM task_assignees WHERE user_id = @user
+	UNION SELECT task_id FROM task_watchers WHERE user_id = @user` // email пользователя в карточках (исполнитель/наблюдатель)
+	userDataTasks = userShownTasks + `
+	UNION SELECT task_id FROM comments WHERE author_id = @user
+	UNION SELECT task_id FROM time_entries WHERE user_id = @user
+	UNION SELECT task_id FROM task_field_values WHERE user_value = @user` // + comment_count, tracked_seconds и поля, которые изменит каскад при удалении (ответы на его комментарии — в тех же задачах)
+)
+
+func touchUserTasks(tx *gorm.DB, tasks string, userID uint) error { // задачи из выборки tasks: новая версия и updated_at
+	return tx.Unscoped().Model(&types.Task{}).Where("id IN ("+tasks+")", map[string]any{"user": userID}).
+		UpdateColumns(map[string]any{"version": gorm.Expr("version + 1"), "updated_at": time.Now()}).Error
+}
+
+func (r *UserGormRepository) SetRoleByEmails(ctx context.Context, emails []string, role string) error { // выдать роль по email
+	if len(emails) == 0 { // нечего делать
+		return nil
+	}
+	return conn(ctx, r.db).Model(&types.User{}).
+		Where("email IN ?", emails). // WHERE email IN (...)
+		Update("role", role).Error   // UPDATE ... SET role=?
+}
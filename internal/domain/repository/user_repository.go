@@ -0,0 +1,17 @@
+package repository // интерфейс репозитория пользователей
+
+import (
+	"context"                            // ctx
+	"task-tracker/internal/domain/types" // модели
+)
+
+type UserRepository interface { // контракт хранилища пользователей
+	Create(ctx context.Context, user *types.User) error                            // создать
+	List(ctx context.Context, limit, offset int) ([]types.User, error)             // список
+	GetByID(ctx context.Context, id uint) (*types.User, error)                     // получить по id
+	GetByEmail(ctx context.Context, email string) (*types.User, error)             // получить по email
+	Exists(ctx context.Context, id uint) (bool, error)                             // есть ли пользователь
+	Update(ctx context.Context, id uint, email, role *string) (*types.User, error) // обновить частично
+	SetRoleByEmails(ctx context.Context, emails []string, role string) error       // выдать роль по списку email
+	Delete(ctx context.Context, id uint) ([]string, error)                         // удалить + ключи его вложений в BlobStore
+}
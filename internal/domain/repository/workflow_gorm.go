@@ -0,0 +1,45 @@
+package repository // реализации репозиториев
+
+import (
+	"context" // ctx
+	"errors"  // errors.Is
+
+	"task-tracker/internal/domain/types" // модели
+
+	"gorm.io/gorm"        // GORM
+	"gorm.io/gorm/clause" // ON CONFLICT
+)
+
+type WorkflowGormRepository struct { // repo процессов на GORM
+	db *gorm.DB // подключение
+}
+
+func NewWorkflowGormRepository(db *gorm.DB) *WorkflowGormRepository { // конструктор
+	return &WorkflowGormRepository{db: db} // сохранить db
+}
+
+func (r *WorkflowGormRepository) Get(ctx context.Context, workspaceID uint) (*types.Workflow, error) { // процесс пространства
+	var wf types.Workflow                                                          // объект
+	err := conn(ctx, r.db).Where("workspace_id = ?", workspaceID).First(&wf).Error // SELECT ... WHERE workspace_id=?
+	if err != nil {                                                                // обработка ошибок
+		if errors.Is(err, gorm.ErrRecordNotFound) { // не настраивали
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &wf, nil // вернуть
+}
+
+func (r *WorkflowGormRepository) Save(ctx context.Context, wf *types.Workflow) error { // upsert процесса
+	return conn(ctx, r.db).Omit("Workspace").
+		Clauses(clause.OnConflict{UpdateAll: true}). // INSERT ... ON CONFLICT (workspace_id) DO UPDATE
+		Create(wf).Error
+}
+
+func (r *WorkflowGormRepository) UsedStatuses(ctx context.Context, workspaceID uint) ([]string, error) { // занятые статусы
+	var statuses []string // результат
+	err := conn(ctx, r.db).Unscoped().Model(&types.Task{}).
+		Where("workspace_id = ?", workspaceID).     // задачи пространства (и из корзины — их ещё могут вернуть)
+		Distinct().Pluck("status", &statuses).Error // SELECT DISTINCT status
+	return statuses, err
+}
@@ -0,0 +1,13 @@
+package repository // интерфейс репозитория процессов
+
+import (
+	"context" // ctx
+
+	"task-tracker/internal/domain/types" // модели
+)
+
+type WorkflowRepository interface { // контракт хранилища процессов пространств
+	Get(ctx context.Context, workspaceID uint) (*types.Workflow, error)   // процесс (ErrNotFound = дефолтный)
+	Save(ctx context.Context, wf *types.Workflow) error                   // создать/заменить
+	UsedStatuses(ctx context.Context, workspaceID uint) ([]string, error) // статусы, в которых есть задачи
+}
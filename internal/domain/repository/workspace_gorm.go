@@ -0,0 +1,223 @@
+package repository // реализации репозиториев
+
+import (
+	"context" // ctx
+	"errors"  // errors.Is
+	"time"    // now
+
+	"task-tracker/internal/domain/types" // модели
+
+	"gorm.io/gorm" // GORM
+)
+
+type WorkspaceGormRepository struct { // repo пространств на GORM
+	db *gorm.DB // подключение
+}
+
+func NewWorkspaceGormRepository(db *gorm.DB) *WorkspaceGormRepository { // конструктор
+	return &WorkspaceGormRepository{db: db} // сохранить db
+}
+
+func (r *WorkspaceGormRepository) Create(ctx context.Context, ws *types.Workspace, ownerID uint) error { // создать + владелец
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error { // всё или ничего
+		if err := tx.Omit("Members").Create(ws).Error; err != nil { // INSERT workspace
+			return err
+		}
+		return tx.Create(&types.WorkspaceMember{ // INSERT owner
+			WorkspaceID: ws.ID,
+			UserID:      ownerID,
+			Role:        types.WorkspaceRoleOwner,
+		}).Error
+	})
+}
+
+func (r *WorkspaceGormRepository) List(ctx context.Context, scope Scope) ([]types.Workspace, error) { // доступные пространства
+	var list []types.Workspace // результат
+
+	q := conn(ctx, r.db).Model(&types.Workspace{}).Order("id") // базовый запрос
+	if !scope.All {                                            // только где участник
+		q = q.Where("id IN (SELECT workspace_id FROM workspace_members WHERE user_id = ?)", scope.UserID)
+	}
+
+	err := q.Find(&list).Error // выполнить SELECT
+	return list, err           // вернуть
+}
+
+func (r *WorkspaceGormRepository) GetByID(ctx context.Context, id uint) (*types.Workspace, error) { // получить по id
+	var ws types.Workspace                      // объект
+	err := conn(ctx, r.db).First(&ws, id).Error // SELECT ... WHERE id=?
+	if err != nil {                             // обработка ошибок
+		if errors.Is(err, gorm.ErrRecordNotFound) { // нет записи
+			return nil, ErrNotFound // доменная not found
+		}
+		return nil, err // прочие ошибки
+	}
+	return &ws, nil // вернуть
+}
+
+func (r *WorkspaceGormRepository) Update(ctx context.Context, id uint, patch types.WorkspacePatch) (*types.Workspace, error) { // частичный апдейт
+	ws, err := r.GetByID(ctx, id) // загрузить
+	if err != nil {
+		return nil, err // ErrNotFound уже тут
+	}
+	patch.Apply(ws)                                                        // применить изменения
+	if err := conn(ctx, r.db).Omit("Members").Save(ws).Error; err != nil { // сохранить
+		return nil, err
+	}
+	return ws, nil // вернуть
+}
+
+func (r *WorkspaceGormRepository) Delete(ctx context.Context, id uint) ([]string, error) { // удалить по id
+	var keys []string
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error { // ключи и удаление по одному снимку
+		err := tx.Model(&types.Attachment{}).
+			Where("task_id IN (SELECT id FROM tasks WHERE workspace_id = ?)", id). // и задачи из корзины; строки уйдут каскадом, объекты — нет
+			Pluck("storage_key", &keys).Error
+		if err != nil {
+			return err
+		}
+		res := tx.Delete(&types.Workspace{}, id) // DELETE ... WHERE id=? (каскад в БД)
+		if res.Error != nil {                    // ошибка
+			return res.Error
+		}
+		if res.RowsAffected == 0 { // не удалилось
+			return ErrNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil // ok
+}
+
+func (r *WorkspaceGormRepository) GetMember(ctx context.Context, workspaceID, userID uint) (*types.WorkspaceMember, error) { // членство
+	var m types.WorkspaceMember // объект
+	err := conn(ctx, r.db).
+		Where("workspace_id = ? AND user_id = ?", workspaceID, userID). // WHERE по PK
+		First(&m).Error
+	if err != nil { // обработка ошибок
+		if errors.Is(err, gorm.ErrRecordNotFound) { // не участник
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &m, nil // вернуть
+}
+
+func (r *WorkspaceGormRepository) ListMembers(ctx context.Context, workspaceID uint) ([]types.WorkspaceMember, error) { // участники
+	var members []types.WorkspaceMember // результат
+	err := conn(ctx, r.db).
+		Preload("User").                                  // email для ответа
+		Where("workspace_id = ?", workspaceID).           // WHERE workspace_id=?
+		Order("created_at, user_id").Find(&members).Error // по времени вступления
+	return members, err // вернуть
+}
+
+func (r *WorkspaceGormRepository) CountByRole(ctx context.Context, workspaceID uint, role string) (int64, error) { // участники с ролью
+	var n int64 // счётчик
+	err := conn(ctx, r.db).Model(&types.WorkspaceMember{}).
+		Where("workspace_id = ? AND role = ?", workspaceID, role). // WHERE ...
+		Count(&n).Error                                            // SELECT count(*)
+	return n, err
+}
+
+func (r *WorkspaceGormRepository) UpdateMemberCapacity(ctx context.Context, workspaceID, userID uint, capacity *float64) error { // своя ёмкость участника
+	res := conn(ctx, r.db).Model(&types.WorkspaceMember{}).
+		Where("workspace_id = ? AND user_id = ?", workspaceID, userID). // WHERE по PK
+		Update("weekly_capacity", capacity)                             // NULL = как у пространства
+	if res.Error != nil { // ошибка
+		return res.Error
+	}
+	if res.RowsAffected == 0 { // не участник
+		return ErrNotFound
+	}
+	return nil // ok
+}
+
+func (r *WorkspaceGormRepository) UpdateMemberRole(ctx context.Context, workspaceID, userID uint, role string) error { // сменить роль
+	res := conn(ctx, r.db).Model(&types.WorkspaceMember{}).
+		Where("workspace_id = ? AND user_id = ?", workspaceID, userID). // WHERE по PK
+		Update("role", role)                                            // UPDATE ... SET role=?
+	if res.Error != nil { // ошибка
+		return res.Error
+	}
+	if res.RowsAffected == 0 { // не участник
+		return ErrNotFound
+	}
+	return nil // ok
+}
+
+func (r *WorkspaceGormRepository) RemoveMember(ctx context.Context, workspaceID, userID uint) error { // исключить
+	res := conn(ctx, r.db).
+		Where("workspace_id = ? AND user_id = ?", workspaceID, userID). // WHERE по PK
+		Delete(&types.WorkspaceMember{})                                // DELETE
+	if res.Error != nil { // ошибка
+		return res.Error
+	}
+	if res.RowsAffected == 0 { // не участник
+		return ErrNotFound
+	}
+	return nil // ok
+}
+
+func (r *WorkspaceGormRepository) CreateInvitation(ctx context.Context, inv *types.WorkspaceInvitation) error { // сохранить приглашение
+	return conn(ctx, r.db).Omit("Workspace").Create(inv).Error // INSERT invitation
+}
+
+func (r *WorkspaceGormRepository) ListInvitations(ctx context.Context, workspaceID uint) ([]types.WorkspaceInvitation, error) { // ожидающие
+	var list []types.WorkspaceInvitation // результат
+	err := conn(ctx, r.db).
+		Where("workspace_id = ? AND accepted_at IS NULL AND expires_at > ?", workspaceID, time.Now()). // только живые
+		Order("id").Find(&list).Error
+	return list, err // вернуть
+}
+
+func (r *WorkspaceGormRepository) GetInvitationByHash(ctx context.Context, hash string) (*types.WorkspaceInvitation, error) { // по хешу токена
+	var inv types.WorkspaceInvitation                                      // объект
+	err := conn(ctx, r.db).Where("token_hash = ?", hash).First(&inv).Error // SELECT ... WHERE token_hash=?
+	if err != nil {                                                        // обработка ошибок
+		if errors.Is(err, gorm.ErrRecordNotFound) { // нет записи
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &inv, nil // вернуть
+}
+
+func (r *WorkspaceGormRepository) DeleteInvitation(ctx context.Context, workspaceID, id uint) error { // отозвать приглашение
+	res := conn(ctx, r.db).
+		Where("id = ? AND workspace_id = ? AND accepted_at IS NULL", id, workspaceID). // только ожидающее
+		Delete(&types.WorkspaceInvitation{})                                           // DELETE
+	if res.Error != nil { // ошибка
+		return res.Error
+	}
+	if res.RowsAffected == 0 { // нет / уже принято
+		return ErrNotFound
+	}
+	return nil // ok
+}
+
+func (r *WorkspaceGormRepository) AcceptInvitation(ctx context.Context, inv *types.WorkspaceInvitation, userID uint) error { // принять
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error { // всё или ничего
+		res := tx.Model(&types.WorkspaceInvitation{}).
+			Where("id = ? AND accepted_at IS NULL", inv.ID). // защита от двойного принятия
+			Update("accepted_at", time.Now())                // UPDATE ... SET accepted_at=now
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 { // уже принято параллельно
+			return ErrNotFound
+		}
+
+		err := tx.Create(&types.WorkspaceMember{ // INSERT member
+			WorkspaceID: inv.WorkspaceID,
+			UserID:      userID,
+			Role:        inv.Role,
+		}).Error
+		if errors.Is(err, gorm.ErrDuplicatedKey) { // уже участник
+			return ErrDuplicate
+		}
+		return err
+	})
+}
@@ -0,0 +1,27 @@
+package repository // интерфейс репозитория рабочих пространств
+
+import (
+	"context" // ctx
+
+	"task-tracker/internal/domain/types" // модели
+)
+
+type WorkspaceRepository interface { // контракт хранилища пространств, участников и приглашений
+	Create(ctx context.Context, ws *types.Workspace, ownerID uint) error                         // создать + владелец (атомарно)
+	List(ctx context.Context, scope Scope) ([]types.Workspace, error)                            // доступные пространства
+	GetByID(ctx context.Context, id uint) (*types.Workspace, error)                              // получить
+	Update(ctx context.Context, id uint, patch types.WorkspacePatch) (*types.Workspace, error)   // изменить название/настройки
+	Delete(ctx context.Context, id uint) ([]string, error)                                       // удалить (каскадно) + ключи вложений его задач в BlobStore
+	GetMember(ctx context.Context, workspaceID, userID uint) (*types.WorkspaceMember, error)     // членство
+	ListMembers(ctx context.Context, workspaceID uint) ([]types.WorkspaceMember, error)          // участники (с User)
+	CountByRole(ctx context.Context, workspaceID uint, role string) (int64, error)               // сколько участников с ролью
+	UpdateMemberRole(ctx context.Context, workspaceID, userID uint, role string) error           // сменить роль
+	UpdateMemberCapacity(ctx context.Context, workspaceID, userID uint, capacity *float64) error // своя ёмкость (nil = как у пространства)
+	RemoveMember(ctx context.Context, workspaceID, userID uint) error                            // исключить
+
+	CreateInvitation(ctx context.Context, inv *types.WorkspaceInvitation) error                 // сохранить приглашение
+	ListInvitations(ctx context.Context, workspaceID uint) ([]types.WorkspaceInvitation, error) // ожидающие приглашения
+	GetInvitationByHash(ctx context.Context, hash string) (*types.WorkspaceInvitation, error)   // найти по хешу токена
+	DeleteInvitation(ctx context.Context, workspaceID, id uint) error                           // отозвать
+	AcceptInvitation(ctx context.Context, inv *types.WorkspaceInvitation, userID uint) error    // принять: участник + отметка (атомарно)
+}
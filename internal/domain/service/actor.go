@@ -0,0 +1,52 @@
+package service // сервисный слой
+
+import (
+	"context" // ctx
+
+	"task-tracker/internal/domain/repository" // Scope
+	"task-tracker/internal/domain/types"      // роли
+)
+
+type Actor struct { // кто выполняет запрос
+	UserID   uint     // id пользователя
+	Role     string   // types.RoleUser / types.RoleAdmin
+	APIKeyID uint     // через какой API-ключ (0 = сессия по JWT)
+	Scopes   []string // права ключа (для сессии не используются)
+}
+
+type actorKey struct{} // ключ в context.Context
+
+func WithActor(ctx context.Context, actor Actor) context.Context { // положить вызывающего в ctx
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+func ActorFrom(ctx context.Context) (Actor, bool) { // достать вызывающего из ctx
+	actor, ok := ctx.Value(actorKey{}).(Actor)
+	return actor, ok && actor.UserID != 0
+}
+
+func (a Actor) IsAdmin() bool { return a.Role == types.RoleAdmin } // админ?
+
+func (a Actor) HasScope(scope string) bool { // разрешено ли действие
+	if a.APIKeyID == 0 { // интерактивная сессия — полный доступ
+		return true
+	}
+	for _, s := range a.Scopes { // ключ — только выданные права
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func (a Actor) Scope() repository.Scope { // ограничение выборки для репозиториев
+	return repository.Scope{UserID: a.UserID, All: a.IsAdmin()}
+}
+
+func requireActor(ctx context.Context) (Actor, error) { // вызывающий обязателен
+	actor, ok := ActorFrom(ctx)
+	if !ok { // запрос без аутентификации
+		return Actor{}, Unauthorized(nil)
+	}
+	return actor, nil
+}
@@ -0,0 +1,131 @@
+package service // сервисный слой
+
+import (
+	"context"         // ctx
+	"crypto/rand"     // генерация ключа
+	"encoding/base64" // кодирование ключа
+	"errors"          // errors.Is
+	"slices"          // Contains/Sort
+	"strings"         // TrimSpace/Join
+	"time"            // last_used_at
+
+	"task-tracker/internal/domain/repository" // repo интерфейсы + ошибки
+	"task-tracker/internal/domain/types"      // модели
+)
+
+const (
+	apiKeyPrefix        = "tt_"          // по префиксу ключ отличается от JWT
+	apiKeyTouchInterval = time.Minute    // не пишем last_used_at чаще раза в минуту
+	apiKeyShownPrefix   = len("tt_") + 8 // сколько символов ключа показываем в списке
+)
+
+type APIKeyService struct { // сервис API-ключей
+	repo  repository.APIKeyRepository // ключи
+	users repository.UserRepository   // владельцы
+}
+
+func NewAPIKeyService(repo repository.APIKeyRepository, users repository.UserRepository) *APIKeyService { // конструктор
+	return &APIKeyService{repo: repo, users: users} // сохранить repo
+}
+
+func IsAPIKey(token string) bool { return strings.HasPrefix(token, apiKeyPrefix) } // ключ или JWT?
+
+func (s *APIKeyService) Create(ctx context.Context, name string, scopes []string) (*types.APIKey, string, error) { // выпустить ключ
+	actor, err := requireActor(ctx) // ключ выпускается себе
+	if err != nil {
+		return nil, "", err
+	}
+
+	name = strings.TrimSpace(name) // чистим name
+	if name == "" || len(name) > 100 {
+		return nil, "", Validation(map[string]string{"name": "must be 1..100 chars"})
+	}
+	if len(scopes) == 0 { // ключ без прав бессмыслен
+		return nil, "", Validation(map[string]string{"scopes": "required"})
+	}
+	for _, sc := range scopes { // только известные scopes
+		if !slices.Contains(types.APIKeyScopes, sc) {
+			return nil, "", Validation(map[string]string{"scopes": "unknown scope " + sc})
+		}
+	}
+	scopes = slices.Clone(scopes) // не трогаем чужой слайс
+	slices.Sort(scopes)
+	scopes = slices.Compact(scopes) // без дублей
+
+	raw := make([]byte, 32)                   // 256 бит энтропии
+	if _, err := rand.Read(raw); err != nil { // crypto/rand
+		return nil, "", Internal(err)
+	}
+	plain := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(raw) // отдаём клиенту один раз
+
+	key := &types.APIKey{ // в БД только хеш
+		UserID:  actor.UserID,
+		Name:    name,
+		Prefix:  plain[:apiKeyShownPrefix],
+		KeyHash: hashToken(plain),
+		Scopes:  strings.Join(scopes, " "),
+	}
+	if err := s.repo.Create(ctx, key); err != nil { // записываем в БД
+		return nil, "", Internal(err)
+	}
+	return key, plain, nil // ok
+}
+
+func (s *APIKeyService) List(ctx context.Context) ([]types.APIKey, error) { // свои ключи
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := s.repo.ListByUser(ctx, actor.UserID)
+	if err != nil {
+		return nil, Internal(err)
+	}
+	return keys, nil
+}
+
+func (s *APIKeyService) Revoke(ctx context.Context, id uint) error { // отозвать свой ключ
+	if id == 0 { // id обязателен
+		return Validation(map[string]string{"id": "required"})
+	}
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Revoke(ctx, actor.UserID, id); err != nil { // только свой
+		if errors.Is(err, repository.ErrNotFound) {
+			return NotFound(nil)
+		}
+		return Internal(err)
+	}
+	return nil // ok
+}
+
+func (s *APIKeyService) Authenticate(ctx context.Context, plain string) (*types.User, *types.APIKey, error) { // ключ -> пользователь
+	key, err := s.repo.GetByHash(ctx, hashToken(plain)) // ищем по хешу
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) { // неизвестный ключ
+			return nil, nil, Unauthorized(map[string]string{"api_key": "invalid"})
+		}
+		return nil, nil, Internal(err)
+	}
+	if key.RevokedAt != nil { // отозван
+		return nil, nil, Unauthorized(map[string]string{"api_key": "revoked"})
+	}
+
+	user, err := s.users.GetByID(ctx, key.UserID) // владелец ключа
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, nil, Unauthorized(map[string]string{"api_key": "invalid"})
+		}
+		return nil, nil, Internal(err)
+	}
+
+	now := time.Now()                                                             // момент использования
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval { // не пишем на каждый запрос
+		if err := s.repo.TouchLastUsed(ctx, key.ID, now); err != nil {
+			return nil, nil, Internal(err)
+		}
+		key.LastUsedAt = &now
+	}
+	return user, key, nil // ok
+}
@@ -3,9 +3,15 @@ package service // ошибки сервисного слоя
 type Code string // тип для кодов ошибок
 
 const (
-	CodeValidation Code = "validation_error" // неверные данные
-	CodeNotFound   Code = "not_found"        // не найдено
-	CodeInternal   Code = "internal_error"   // внутренняя ошибка
+	CodeValidation        Code = "validation_error"    // неверные данные
+	CodeNotFound          Code = "not_found"           // не найдено
+	CodeUnauthorized      Code = "unauthorized"        // нет/неверные учётные данные
+	CodeForbidden         Code = "forbidden"           // аутентифицирован, но нет прав
+	CodeConflict          Code = "conflict"            // конфликт с текущим состоянием (уникальность и т.п.)
+	CodePrecondition      Code = "precondition_failed" // If-Match не совпал с текущей версией
+	CodeInvalidTransition Code = "invalid_transition"  // переход статуса запрещён процессом
+	CodeTooLarge          Code = "too_large"           // тело/файл больше лимита
+	CodeInternal          Code = "internal_error"      // внутренняя ошибка
 )
 
 type AppError struct { // единый тип ошибки сервиса
@@ -24,6 +30,12 @@ func (e *AppError) Error() string { // текст ошибки
 func (e *AppError) Unwrap() error { return e.err } // для errors.Is/As
 
 // helpers
-func Validation(details any) error { return &AppError{Code: CodeValidation, Details: details} } // создать validation
-func NotFound(details any) error   { return &AppError{Code: CodeNotFound, Details: details} }   // создать not_found
-func Internal(err error) error     { return &AppError{Code: CodeInternal, err: err} }           // создать internal
+func Validation(details any) error         { return &AppError{Code: CodeValidation, Details: details} }   // создать validation
+func NotFound(details any) error           { return &AppError{Code: CodeNotFound, Details: details} }     // создать not_found
+func Conflict(details any) error           { return &AppError{Code: CodeConflict, Details: details} }     // создать conflict
+func Unauthorized(details any) error       { return &AppError{Code: CodeUnauthorized, Details: details} } // создать unauthorized
+func Forbidden(details any) error          { return &AppError{Code: CodeForbidden, Details: details} }    // создать forbidden
+func InvalidTransition(details any) error  { return &AppError{CodeInvalidTransition, details, nil} }      // создать invalid_transition
+func PreconditionFailed(details any) error { return &AppError{CodePrecondition, details, nil} }           // создать precondition_failed
+func TooLarge(details any) error           { return &AppError{Code: CodeTooLarge, Details: details} }     // создать too_large
+func Internal(err error) error             { return &AppError{Code: CodeInternal, err: err} }             // создать internal
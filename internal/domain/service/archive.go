@@ -0,0 +1,105 @@
+package service // сервисный слой
+
+import (
+	"context" // ctx
+	"errors"  // errors.Is
+	"time"    // archived_at
+
+	"task-tracker/internal/domain/repository" // repo интерфейсы + ошибки
+	"task-tracker/internal/domain/types"      // модели
+)
+
+const maxArchiveAgeDays = 3650 // "старше N дней" — не больше 10 лет
+
+func (s *TaskService) Archive(ctx context.Context, id uint) (*types.Task, error) { // убрать задачу в архив
+	now := time.Now()
+	return s.setArchived(ctx, id, &now)
+}
+
+func (s *TaskService) Unarchive(ctx context.Context, id uint) (*types.Task, error) { // вернуть задачу из архива
+	return s.setArchived(ctx, id, nil)
+}
+
+func (s *TaskService) setArchived(ctx context.Context, id uint, at *time.Time) (*types.Task, error) { // archived_at = at
+	actor, current, err := s.authorizeWrite(ctx, id) // видна + есть права на запись
+	if err != nil {
+		return nil, err
+	}
+	if (current.ArchivedAt != nil) == (at != nil) { // уже в нужном состоянии — ничего не трогаем
+		return current, nil
+	}
+	action := types.RevisionUnarchive
+	if at != nil {
+		action = types.RevisionArchive
+	}
+	var task *types.Task
+	err = s.inTx(ctx, func(ctx context.Context) error { // архивация и ревизия — вместе
+		if err := s.repo.SetArchived(ctx, actor.Scope(), id, at); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return NotFound(nil)
+			}
+			return Internal(err)
+		}
+		var err error
+		if task, err = s.GetByID(ctx, id); err != nil {
+			return err
+		}
+		return s.record(ctx, actor, action, current, task, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+func (s *TaskService) ArchiveDone(ctx context.Context, workspaceID *uint, olderThanDays int) (int64, error) { // в архив все выполненные больше N дней назад
+	if olderThanDays < 0 || olderThanDays > maxArchiveAgeDays {
+		return 0, Validation(map[string]string{"older_than_days": "must be 0..3650"})
+	}
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return 0, err
+	}
+	scope := repository.Scope{UserID: actor.UserID} // без workspace_id — только свои личные, даже у админа
+	if workspaceID != nil {                         // задачи команды — нужна роль member+
+		if err := requireWorkspaceRole(ctx, s.workspaces, actor, *workspaceID, types.WorkspaceRoleMember); err != nil {
+			return 0, err
+		}
+		scope = actor.Scope()
+	}
+	return s.archiveDone(ctx, actor, scope, workspaceID, time.Now().AddDate(0, 0, -olderThanDays))
+}
+
+func (s *TaskService) AutoArchive(ctx context.Context) (int64, error) { // фоновая архивация по AUTO_ARCHIVE_AFTER
+	if s.autoArchiveAfter <= 0 { // выключено
+		return 0, nil
+	}
+	return s.archiveDone(ctx, Actor{}, repository.Scope{All: true}, nil, time.Now().Add(-s.autoArchiveAfter)) // без пользователя — ревизии от системы
+}
+
+func (s *TaskService) archiveDone(ctx context.Context, actor Actor, scope repository.Scope, workspaceID *uint, before time.Time) (int64, error) { // архивация и ревизия каждой задачи — вместе
+	var n int64
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		ids, err := s.repo.ArchiveDone(ctx, scope, workspaceID, before)
+		if err != nil {
+			return Internal(err)
+		}
+		for _, id := range ids {
+			task, err := s.repo.GetByID(ctx, repository.Scope{All: true}, id) // id уже из видимых
+			if err != nil {
+				return Internal(err)
+			}
+			prev := *task // до архивации отличалась только archived_at
+			prev.ArchivedAt = nil
+			if err := s.record(ctx, actor, types.RevisionArchive, &prev, task, nil); err != nil {
+				return err
+			}
+		}
+		n = int64(len(ids))
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
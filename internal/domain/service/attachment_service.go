@@ -0,0 +1,188 @@
+package service // сервисный слой
+
+import (
+	"context"       // ctx
+	"crypto/rand"   // ключ объекта
+	"crypto/sha256" // контрольная сумма
+	"encoding/hex"  // hex
+	"errors"        // errors.Is
+	"fmt"           // ключ объекта
+	"io"            // потоки
+	"net/http"      // DetectContentType
+	"path"          // Base
+	"strings"       // очистка имени
+	"unicode"       // управляющие символы
+	"unicode/utf8"  // обрезка имени
+
+	"task-tracker/internal/domain/repository" // repo интерфейсы + ошибки
+	"task-tracker/internal/domain/types"      // модели
+	"task-tracker/internal/storage"           // BlobStore
+)
+
+type AttachmentService struct { // сервис вложений
+	repo       repository.AttachmentRepository // метаданные
+	tasks      repository.TaskRepository       // видимость задачи
+	workspaces repository.WorkspaceRepository  // роли в пространствах
+	blobs      storage.BlobStore               // байты
+	maxBytes   int64                           // лимит размера
+}
+
+func NewAttachmentService(repo repository.AttachmentRepository, tasks repository.TaskRepository, workspaces repository.WorkspaceRepository, blobs storage.BlobStore, maxBytes int64) *AttachmentService { // конструктор
+	return &AttachmentService{repo: repo, tasks: tasks, workspaces: workspaces, blobs: blobs, maxBytes: maxBytes} // сохранить зависимости
+}
+
+func (s *AttachmentService) MaxBytes() int64 { return s.maxBytes } // лимит (для MaxBytesReader в хендлере)
+
+func cleanFilename(name string) string { // без пути, управляющих символов и не длиннее 255 байт
+	name = path.Base(strings.ReplaceAll(name, "\\", "/")) // C:\\x\\a.png -> a.png
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' { // ломают Content-Disposition
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	for len(name) > 255 { // по рунам, чтобы не резать UTF-8
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	if name == "" || name == "." || name == "/" {
+		return "file"
+	}
+	return name
+}
+
+func (s *AttachmentService) Upload(ctx context.Context, taskID uint, filename string, size int64, r io.ReadSeeker) (*types.Attachment, error) { // загрузить вложение
+	if size <= 0 {
+		return nil, Validation(map[string]string{"file": "empty"})
+	}
+	if size > s.maxBytes {
+		return nil, TooLarge(map[string]any{"file": "too large", "max_bytes": s.maxBytes})
+	}
+	actor, _, err := authorizeTaskWrite(ctx, s.tasks, s.workspaces, taskID) // видна + есть права на запись
+	if err != nil {
+		return nil, err
+	}
+
+	head := make([]byte, 512) // по содержимому, а не по заявленному типу
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, Internal(err)
+	}
+	contentType := http.DetectContentType(head[:n])
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, Internal(err)
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return nil, Internal(err)
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, Internal(err)
+	}
+
+	rnd := make([]byte, 16) // непредсказуемый ключ
+	if _, err := rand.Read(rnd); err != nil {
+		return nil, Internal(err)
+	}
+	key := fmt.Sprintf("tasks/%d/%s", taskID, hex.EncodeToString(rnd))
+	if err := s.blobs.Put(ctx, key, r, size, contentType); err != nil {
+		return nil, Internal(err)
+	}
+
+	a := &types.Attachment{ // собираем модель
+		TaskID:      taskID,
+		UploaderID:  actor.UserID,
+		Filename:    cleanFilename(filename),
+		ContentType: contentType,
+		Size:        size,
+		SHA256:      hex.EncodeToString(h.Sum(nil)),
+		StorageKey:  key,
+	}
+	if err := s.repo.Create(ctx, a); err != nil {
+		_ = s.blobs.Delete(context.WithoutCancel(ctx), key) // не оставляем сироту
+		return nil, Internal(err)
+	}
+	return a, nil
+}
+
+func (s *AttachmentService) List(ctx context.Context, taskID uint) ([]types.Attachment, error) { // вложения задачи
+	if err := s.requireTask(ctx, taskID); err != nil {
+		return nil, err
+	}
+	list, err := s.repo.ListByTask(ctx, taskID)
+	if err != nil {
+		return nil, Internal(err)
+	}
+	return list, nil
+}
+
+func (s *AttachmentService) Open(ctx context.Context, taskID, id uint) (*types.Attachment, io.ReadCloser, error) { // метаданные + содержимое (закрывает вызывающий)
+	if err := s.requireTask(ctx, taskID); err != nil {
+		return nil, nil, err
+	}
+	a, err := s.repo.GetByID(ctx, taskID, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, nil, NotFound(nil)
+		}
+		return nil, nil, Internal(err)
+	}
+	body, err := s.blobs.Get(ctx, a.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrBlobNotFound) { // метаданные есть, байтов нет
+			return nil, nil, NotFound(map[string]string{"attachment": "content is missing"})
+		}
+		return nil, nil, Internal(err)
+	}
+	return a, body, nil
+}
+
+func (s *AttachmentService) Delete(ctx context.Context, taskID, id uint) error { // удалить вложение
+	if _, _, err := authorizeTaskWrite(ctx, s.tasks, s.workspaces, taskID); err != nil { // видна + есть права на запись
+		return err
+	}
+	a, err := s.repo.GetByID(ctx, taskID, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return NotFound(nil)
+		}
+		return Internal(err)
+	}
+	if err := s.repo.Delete(ctx, taskID, id); err != nil { // сначала строку: без неё объект недостижим
+		if errors.Is(err, repository.ErrNotFound) {
+			return NotFound(nil)
+		}
+		return Internal(err)
+	}
+	if err := s.blobs.Delete(ctx, a.StorageKey); err != nil {
+		return Internal(err)
+	}
+	return nil
+}
+
+func deleteBlobs(ctx context.Context, blobs storage.BlobStore, keys []string) error { // объекты вложений, чьи строки удалены каскадом
+	ctx = context.WithoutCancel(ctx) // строк уже нет: прерванный запрос не должен оставить сирот
+	var errs []error
+	for _, key := range keys {
+		if err := blobs.Delete(ctx, key); err != nil { // остальные всё равно пробуем
+			errs = append(errs, fmt.Errorf("blob %s: %w", key, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *AttachmentService) requireTask(ctx context.Context, taskID uint) error { // задача видна вызывающему
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := s.tasks.GetByID(ctx, actor.Scope(), taskID); err != nil { // чужая = not_found
+		if errors.Is(err, repository.ErrNotFound) {
+			return NotFound(nil)
+		}
+		return Internal(err)
+	}
+	return nil
+}
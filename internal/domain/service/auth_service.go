@@ -0,0 +1,223 @@
+package service // сервисный слой
+
+import (
+	"context"         // ctx
+	"crypto/rand"     // случайные refresh-токены
+	"crypto/sha256"   // хеш refresh-токена для БД
+	"encoding/base64" // кодирование токена
+	"encoding/hex"    // hex хеша
+	"errors"          // errors.Is
+	"strconv"         // sub <-> id
+	"sync"            // ленивый dummy-хеш
+	"time"            // TTL
+
+	"github.com/golang-jwt/jwt/v5" // JWT
+	"golang.org/x/crypto/bcrypt"   // хеширование паролей
+
+	"task-tracker/internal/domain/repository" // repo интерфейсы + ошибки
+	"task-tracker/internal/domain/types"      // модели
+)
+
+const tokenIssuer = "task-tracker" // iss в access-токенах
+
+type TokenPair struct { // выданная пара токенов
+	AccessToken  string        // короткоживущий JWT
+	RefreshToken string        // непрозрачный ротируемый токен
+	ExpiresIn    time.Duration // TTL access-токена
+}
+
+type AuthService struct { // сервис аутентификации
+	users      repository.UserRepository         // пользователи
+	tokens     repository.RefreshTokenRepository // refresh-токены
+	secret     []byte                            // ключ HMAC для JWT
+	accessTTL  time.Duration                     // TTL access
+	refreshTTL time.Duration                     // TTL refresh
+	admins     map[string]bool                   // email, которым при регистрации выдаётся admin
+}
+
+func NewAuthService(users repository.UserRepository, tokens repository.RefreshTokenRepository, secret string, accessTTL, refreshTTL time.Duration, adminEmails []string) *AuthService { // конструктор
+	admins := make(map[string]bool, len(adminEmails)) // быстрый поиск
+	for _, e := range adminEmails {
+		admins[e] = true
+	}
+	return &AuthService{
+		users:      users,
+		tokens:     tokens,
+		secret:     []byte(secret),
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+		admins:     admins,
+	}
+}
+
+var (
+	dummyHashOnce sync.Once // считаем один раз
+	dummyHash     []byte    // для сравнения, когда пользователя нет (выравниваем время ответа)
+)
+
+func validatePassword(password string) error { // правила пароля
+	if len(password) < 8 || len(password) > 72 { // bcrypt режет всё после 72 байт
+		return Validation(map[string]string{"password": "must be 8..72 bytes"})
+	}
+	return nil
+}
+
+func (s *AuthService) Signup(ctx context.Context, email, password string) (*types.User, *TokenPair, error) { // регистрация
+	email, ok := normalizeEmail(email) // валидируем email
+	if !ok {
+		return nil, nil, Validation(map[string]string{"email": "invalid"})
+	}
+	if err := validatePassword(password); err != nil { // валидируем пароль
+		return nil, nil, err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost) // хешируем
+	if err != nil {
+		return nil, nil, Internal(err)
+	}
+
+	role := types.RoleUser // по умолчанию обычный пользователь
+	if s.admins[email] {   // bootstrap первого админа через ADMIN_EMAILS
+		role = types.RoleAdmin
+	}
+
+	user := &types.User{Email: email, PasswordHash: string(hash), Role: role} // собираем модель
+	if err := s.users.Create(ctx, user); err != nil {                         // записываем в БД
+		if errors.Is(err, repository.ErrDuplicate) { // email занят
+			return nil, nil, Conflict(map[string]string{"email": "already taken"})
+		}
+		return nil, nil, Internal(err)
+	}
+
+	pair, err := s.issue(ctx, user.ID) // сразу логиним
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, pair, nil // ok
+}
+
+func (s *AuthService) Login(ctx context.Context, email, password string) (*types.User, *TokenPair, error) { // вход по паролю
+	email, _ = normalizeEmail(email) // мусор просто не найдётся
+
+	user, err := s.users.GetByEmail(ctx, email) // ищем пользователя
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, nil, Internal(err)
+	}
+
+	if user == nil || user.PasswordHash == "" { // нет пользователя / без пароля
+		dummyHashOnce.Do(func() { dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost) })
+		_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(password)) // тратим столько же времени
+		return nil, nil, Unauthorized(map[string]string{"credentials": "invalid"})
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil { // неверный пароль
+		return nil, nil, Unauthorized(map[string]string{"credentials": "invalid"})
+	}
+
+	pair, err := s.issue(ctx, user.ID) // новая сессия
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, pair, nil // ok
+}
+
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*TokenPair, error) { // ротация refresh-токена
+	token, err := s.tokens.GetByHash(ctx, hashToken(refreshToken)) // ищем по хешу
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) { // неизвестный токен
+			return nil, Unauthorized(map[string]string{"refresh_token": "invalid"})
+		}
+		return nil, Internal(err)
+	}
+
+	if token.RevokedAt != nil { // повторное использование отозванного -> вероятна кража
+		if err := s.tokens.RevokeAllForUser(ctx, token.UserID); err != nil {
+			return nil, Internal(err)
+		}
+		return nil, Unauthorized(map[string]string{"refresh_token": "revoked"})
+	}
+	if time.Now().After(token.ExpiresAt) { // истёк
+		return nil, Unauthorized(map[string]string{"refresh_token": "expired"})
+	}
+
+	if err := s.tokens.Revoke(ctx, token.ID); err != nil { // гасим старый
+		if errors.Is(err, repository.ErrNotFound) { // параллельная ротация тем же токеном
+			return nil, Unauthorized(map[string]string{"refresh_token": "revoked"})
+		}
+		return nil, Internal(err)
+	}
+
+	return s.issue(ctx, token.UserID) // новая пара
+}
+
+func (s *AuthService) Logout(ctx context.Context, refreshToken string) error { // выход (идемпотентно)
+	token, err := s.tokens.GetByHash(ctx, hashToken(refreshToken)) // ищем по хешу
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) { // уже нет — ок
+			return nil
+		}
+		return Internal(err)
+	}
+	if err := s.tokens.Revoke(ctx, token.ID); err != nil && !errors.Is(err, repository.ErrNotFound) { // отзываем
+		return Internal(err)
+	}
+	return nil // ok
+}
+
+func (s *AuthService) Authenticate(ctx context.Context, accessToken string) (*types.User, error) { // access-токен -> пользователь
+	claims := &jwt.RegisteredClaims{} // sub/exp/iss
+	_, err := jwt.ParseWithClaims(accessToken, claims, func(*jwt.Token) (any, error) {
+		return s.secret, nil // ключ HMAC
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithIssuer(tokenIssuer), jwt.WithExpirationRequired())
+	if err != nil { // подпись/срок/формат
+		return nil, Unauthorized(map[string]string{"token": "invalid"})
+	}
+
+	id64, err := strconv.ParseUint(claims.Subject, 10, 64) // sub = id пользователя
+	if err != nil || id64 == 0 {
+		return nil, Unauthorized(map[string]string{"token": "invalid"})
+	}
+
+	user, err := s.users.GetByID(ctx, uint(id64)) // пользователь мог быть удалён
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, Unauthorized(map[string]string{"token": "invalid"})
+		}
+		return nil, Internal(err)
+	}
+	return user, nil // ok
+}
+
+func (s *AuthService) issue(ctx context.Context, userID uint) (*TokenPair, error) { // выпустить access + refresh
+	now := time.Now() // момент выдачи
+
+	access, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Issuer:    tokenIssuer,                              // iss
+		Subject:   strconv.FormatUint(uint64(userID), 10),   // sub
+		IssuedAt:  jwt.NewNumericDate(now),                  // iat
+		ExpiresAt: jwt.NewNumericDate(now.Add(s.accessTTL)), // exp
+	}).SignedString(s.secret) // подписываем
+	if err != nil {
+		return nil, Internal(err)
+	}
+
+	raw := make([]byte, 32)                   // 256 бит энтропии
+	if _, err := rand.Read(raw); err != nil { // crypto/rand
+		return nil, Internal(err)
+	}
+	refresh := base64.RawURLEncoding.EncodeToString(raw) // отдаём клиенту
+
+	if err := s.tokens.Create(ctx, &types.RefreshToken{ // в БД только хеш
+		UserID:    userID,
+		TokenHash: hashToken(refresh),
+		ExpiresAt: now.Add(s.refreshTTL),
+	}); err != nil {
+		return nil, Internal(err)
+	}
+
+	return &TokenPair{AccessToken: access, RefreshToken: refresh, ExpiresIn: s.accessTTL}, nil // ok
+}
+
+func hashToken(token string) string { // sha256(token) в hex
+	sum := sha256.Sum256([]byte(token)) // хеш
+	return hex.EncodeToString(sum[:])   // hex
+}
@@ -0,0 +1,167 @@
+package service // сервисный слой
+
+import (
+	"context" // ctx
+	"errors"  // errors.As
+	"slices"  // Contains
+	"strings" // Join
+
+	"task-tracker/internal/domain/types" // модели
+)
+
+const maxBulkTasks = 500 // задач за один bulk-запрос
+
+var bulkOperations = []string{types.BulkUpdate, types.BulkDelete, types.BulkArchive, types.BulkAddLabel, types.BulkReassign}
+
+type BulkInput struct { // одна операция над набором задач
+	IDs       []uint            // явный список задач
+	Filter    *types.TaskFilter // или все задачи под фильтром (не больше maxBulkTasks)
+	Operation string            // types.Bulk*
+	Atomic    bool              // всё или ничего (иначе — отчёт по каждой задаче)
+
+	Patch      types.TaskPatch // update: изменения
+	Force      bool            // update: закрывать несмотря на блокеры
+	Children   string          // delete: reject|orphan|cascade
+	LabelID    uint            // add_label: какую метку
+	AssigneeID uint            // reassign: единственный исполнитель
+}
+
+type BulkItemResult struct { // итог по одной задаче
+	TaskID uint
+	Err    error // nil = применено
+}
+
+func (s *TaskService) Bulk(ctx context.Context, in BulkInput) ([]BulkItemResult, error) { // применить операцию ко всем задачам
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	op, err := s.bulkOperation(in)
+	if err != nil {
+		return nil, err
+	}
+
+	if in.Atomic { // одна транзакция: первая ошибка откатывает всё
+		var results []BulkItemResult
+		err := s.inTx(ctx, func(ctx context.Context) error {
+			ids, err := s.bulkTargets(ctx, actor, in)
+			if err != nil {
+				return err
+			}
+			results = make([]BulkItemResult, 0, len(ids))
+			for _, id := range ids {
+				if err := op(ctx, id); err != nil {
+					return bulkItemError(id, err)
+				}
+				results = append(results, BulkItemResult{TaskID: id})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return results, nil
+	}
+
+	ids, err := s.bulkTargets(ctx, actor, in)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]BulkItemResult, 0, len(ids))
+	for _, id := range ids { // каждая задача — своя транзакция, ошибки не мешают остальным
+		err := s.inTx(ctx, func(ctx context.Context) error { return op(ctx, id) })
+		results = append(results, BulkItemResult{TaskID: id, Err: err})
+	}
+	return results, nil
+}
+
+func (s *TaskService) bulkOperation(in BulkInput) (func(ctx context.Context, id uint) error, error) { // проверить параметры один раз, а не на каждой задаче
+	switch in.Operation {
+	case types.BulkUpdate:
+		if in.Patch.Empty() {
+			return nil, Validation(map[string]string{"update": "nothing to update"})
+		}
+		opts := UpdateTaskOptions{Force: in.Force}
+		return func(ctx context.Context, id uint) error {
+			_, err := s.update(ctx, id, in.Patch, opts, nil)
+			return err
+		}, nil
+	case types.BulkDelete:
+		children := in.Children
+		if children == "" {
+			children = types.DeleteChildrenReject
+		}
+		if children != types.DeleteChildrenReject && children != types.DeleteChildrenOrphan && children != types.DeleteChildrenCascade {
+			return nil, Validation(map[string]string{"children": "must be reject|orphan|cascade"})
+		}
+		return func(ctx context.Context, id uint) error { return s.Delete(ctx, id, children, nil) }, nil
+	case types.BulkArchive:
+		return func(ctx context.Context, id uint) error {
+			_, err := s.Archive(ctx, id)
+			return err
+		}, nil
+	case types.BulkAddLabel:
+		if in.LabelID == 0 {
+			return nil, Validation(map[string]string{"label_id": "required"})
+		}
+		return func(ctx context.Context, id uint) error {
+			_, err := s.AddLabel(ctx, id, in.LabelID)
+			return err
+		}, nil
+	case types.BulkReassign:
+		if in.AssigneeID == 0 {
+			return nil, Validation(map[string]string{"assignee_id": "required"})
+		}
+		return func(ctx context.Context, id uint) error {
+			_, err := s.Reassign(ctx, id, in.AssigneeID)
+			return err
+		}, nil
+	}
+	return nil, Validation(map[string]string{"operation": "must be " + strings.Join(bulkOperations, "|")})
+}
+
+func (s *TaskService) bulkTargets(ctx context.Context, actor Actor, in BulkInput) ([]uint, error) { // id задач из списка или фильтра
+	if (len(in.IDs) > 0) == (in.Filter != nil) {
+		return nil, Validation(map[string]string{"ids": "pass either ids or filter"})
+	}
+	if in.Filter == nil {
+		if len(in.IDs) > maxBulkTasks {
+			return nil, Validation(map[string]string{"ids": "at most 500 tasks"})
+		}
+		ids := make([]uint, 0, len(in.IDs))
+		for _, id := range in.IDs {
+			if id == 0 {
+				return nil, Validation(map[string]string{"ids": "must be positive integers"})
+			}
+			if !slices.Contains(ids, id) { // дубли применяем один раз
+				ids = append(ids, id)
+			}
+		}
+		return ids, nil
+	}
+
+	filter := *in.Filter
+	if err := s.resolveFilter(ctx, actor, &filter); err != nil {
+		return nil, err
+	}
+	tasks, err := s.repo.List(ctx, actor.Scope(), filter, maxBulkTasks+1, 0) // +1 — понять, что фильтр слишком широкий
+	if err != nil {
+		return nil, Internal(err)
+	}
+	if len(tasks) > maxBulkTasks {
+		return nil, Validation(map[string]string{"filter": "matches more than 500 tasks, narrow it down"})
+	}
+	ids := make([]uint, 0, len(tasks))
+	for _, t := range tasks {
+		ids = append(ids, t.ID)
+	}
+	return ids, nil
+}
+
+func bulkItemError(id uint, err error) error { // ошибка задачи id в атомарном режиме: тот же код, в деталях — какая задача
+	var appErr *AppError
+	if !errors.As(err, &appErr) || appErr.Code == CodeInternal {
+		return err
+	}
+	return &AppError{Code: appErr.Code, Details: map[string]any{"task_id": id, "error": appErr.Code, "details": appErr.Details}, err: err}
+}
@@ -0,0 +1,151 @@
+package service // сервисный слой
+
+import (
+	"context" // ctx
+	"errors"  // errors.Is/As
+	"slices"  // Sort/Equal
+	"strings" // TrimSpace
+	"time"    // done_at
+
+	"task-tracker/internal/domain/repository" // repo интерфейсы + ошибки
+	"task-tracker/internal/domain/types"      // модели
+)
+
+const maxChecklistItems = 100 // пунктов на задачу
+
+func normalizeChecklistTitle(title string) (string, error) { // trim + длина
+	title = strings.TrimSpace(title)
+	if title == "" || len(title) > 500 {
+		return "", Validation(map[string]string{"title": "must be 1..500 chars"})
+	}
+	return title, nil
+}
+
+func (s *TaskService) AddChecklistItem(ctx context.Context, id uint, title string) (*types.Task, error) { // пункт в конец чек-листа
+	title, err := normalizeChecklistTitle(title)
+	if err != nil {
+		return nil, err
+	}
+	actor, task, err := s.authorizeWrite(ctx, id) // видна + есть права на запись
+	if err != nil {
+		return nil, err
+	}
+	if len(task.Checklist) >= maxChecklistItems {
+		return nil, Validation(map[string]string{"checklist": "too many items"})
+	}
+
+	return s.changeRelations(ctx, actor, task, func(ctx context.Context) error { // карточка со свежим чек-листом
+		if err := s.checklists.Create(ctx, &types.ChecklistItem{TaskID: id, Title: title}); err != nil {
+			return Internal(err)
+		}
+		return nil
+	})
+}
+
+func (s *TaskService) UpdateChecklistItem(ctx context.Context, id, itemID uint, title *string, done *bool) (*types.Task, error) { // переименовать / отметить
+	if title == nil && done == nil { // нечего менять
+		return nil, Validation(map[string]string{"body": "nothing to update"})
+	}
+	if title != nil {
+		t, err := normalizeChecklistTitle(*title)
+		if err != nil {
+			return nil, err
+		}
+		title = &t
+	}
+	actor, current, err := s.authorizeWrite(ctx, id) // видна + есть права на запись
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := s.checklists.GetByID(ctx, id, itemID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, NotFound(map[string]string{"item": "not found"})
+		}
+		return nil, Internal(err)
+	}
+	checked := done != nil && *done && !item.Done // пункт только что отметили
+	if title != nil {
+		item.Title = *title
+	}
+	if done != nil && *done != item.Done {
+		item.Done = *done
+		item.DoneAt = nil
+		if item.Done {
+			now := time.Now()
+			item.DoneAt = &now
+		}
+	}
+	task, err := s.changeRelations(ctx, actor, current, func(ctx context.Context) error { // карточка со свежим чек-листом
+		if err := s.checklists.Save(ctx, item); err != nil {
+			return Internal(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if checked && s.autoCompleteChecklist && !task.Done && task.ChecklistProgress != nil &&
+		task.ChecklistProgress.Done == task.ChecklistProgress.Total { // последний пункт — закрываем задачу
+		return s.autoComplete(ctx, task)
+	}
+	return task, nil
+}
+
+func (s *TaskService) ReorderChecklist(ctx context.Context, id uint, itemIDs []uint) (*types.Task, error) { // новый порядок пунктов
+	actor, task, err := s.authorizeWrite(ctx, id) // видна + есть права на запись
+	if err != nil {
+		return nil, err
+	}
+
+	current := make([]uint, 0, len(task.Checklist)) // должны прислать ровно те же пункты
+	for _, item := range task.Checklist {
+		current = append(current, item.ID)
+	}
+	given := slices.Clone(itemIDs)
+	slices.Sort(current)
+	slices.Sort(given)
+	if !slices.Equal(current, given) {
+		return nil, Validation(map[string]string{"item_ids": "must list every checklist item exactly once"})
+	}
+
+	return s.changeRelations(ctx, actor, task, func(ctx context.Context) error {
+		if err := s.checklists.Reorder(ctx, id, itemIDs); err != nil {
+			if errors.Is(err, repository.ErrNotFound) { // пункт удалили параллельно
+				return Conflict(map[string]string{"item_ids": "checklist changed, reload and retry"})
+			}
+			return Internal(err)
+		}
+		return nil
+	})
+}
+
+func (s *TaskService) DeleteChecklistItem(ctx context.Context, id, itemID uint) (*types.Task, error) { // удалить пункт
+	actor, task, err := s.authorizeWrite(ctx, id) // видна + есть права на запись
+	if err != nil {
+		return nil, err
+	}
+	return s.changeRelations(ctx, actor, task, func(ctx context.Context) error {
+		if err := s.checklists.Delete(ctx, id, itemID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return NotFound(map[string]string{"item": "not found"})
+			}
+			return Internal(err)
+		}
+		return nil
+	})
+}
+
+func (s *TaskService) autoComplete(ctx context.Context, task *types.Task) (*types.Task, error) { // закрыть задачу по чек-листу
+	done := true
+	closed, err := s.Update(ctx, task.ID, types.TaskPatch{Done: &done}, UpdateTaskOptions{})
+	if err == nil {
+		return closed, nil
+	}
+	var appErr *AppError
+	if errors.As(err, &appErr) && (appErr.Code == CodeConflict || appErr.Code == CodeInvalidTransition) {
+		return task, nil // блокеры или процесс не пускают — пункт отмечен, задача остаётся открытой
+	}
+	return nil, err
+}
@@ -0,0 +1,219 @@
+package service // сервисный слой
+
+import (
+	"context"         // ctx
+	"encoding/base64" // курсор
+	"errors"          // errors.Is
+	"strconv"         // курсор
+	"strings"         // TrimSpace
+	"time"            // edited_at
+
+	"task-tracker/internal/domain/repository" // repo интерфейсы + ошибки
+	"task-tracker/internal/domain/types"      // модели
+)
+
+const maxCommentLen = 10000 // ограничение на текст комментария
+
+type CommentService struct { // сервис комментариев
+	repo  repository.CommentRepository // комментарии
+	tasks repository.TaskRepository    // видимость задачи
+}
+
+func NewCommentService(repo repository.CommentRepository, tasks repository.TaskRepository) *CommentService { // конструктор
+	return &CommentService{repo: repo, tasks: tasks} // сохранить зависимости
+}
+
+type CommentPage struct { // страница комментариев
+	Items      []types.Comment // комментарии по возрастанию id
+	NextCursor string          // "" = дальше пусто
+}
+
+func encodeCursor(id uint) string { // id -> непрозрачный курсор
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(uint64(id), 10)))
+}
+
+func decodeCursor(cursor string) (uint, error) { // курсор -> id
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, Validation(map[string]string{"cursor": "invalid"})
+	}
+	id, err := strconv.ParseUint(string(raw), 10, 64)
+	if err != nil || id == 0 {
+		return 0, Validation(map[string]string{"cursor": "invalid"})
+	}
+	return uint(id), nil
+}
+
+func normalizeCommentBody(body string) (string, error) { // trim + длина
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", Validation(map[string]string{"body": "required"})
+	}
+	if len(body) > maxCommentLen {
+		return "", Validation(map[string]string{"body": "too long"})
+	}
+	return body, nil
+}
+
+func (s *CommentService) requireTask(ctx context.Context, taskID uint) (Actor, error) { // задача видна вызывающему
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return Actor{}, err
+	}
+	if _, err := s.tasks.GetByID(ctx, actor.Scope(), taskID); err != nil { // чужая = not_found
+		if errors.Is(err, repository.ErrNotFound) {
+			return Actor{}, NotFound(nil)
+		}
+		return Actor{}, Internal(err)
+	}
+	return actor, nil
+}
+
+func (s *CommentService) Create(ctx context.Context, taskID uint, parentID *uint, body string) (*types.Comment, error) { // написать (достаточно видеть задачу)
+	body, err := normalizeCommentBody(body)
+	if err != nil {
+		return nil, err
+	}
+	actor, err := s.requireTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if parentID != nil { // ответ — в той же задаче
+		parent, err := s.repo.GetByID(ctx, taskID, *parentID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, Validation(map[string]string{"parent_id": "not found"})
+			}
+			return nil, Internal(err)
+		}
+		if parent.DeletedAt != nil { // на удалённый не отвечаем
+			return nil, Validation(map[string]string{"parent_id": "comment was deleted"})
+		}
+	}
+
+	c := &types.Comment{TaskID: taskID, AuthorID: actor.UserID, ParentID: parentID, Body: body} // собираем модель
+	if err := s.repo.Create(ctx, c); err != nil {
+		return nil, Internal(err)
+	}
+	if err := s.tasks.Touch(ctx, taskID); err != nil { // comment_count в карточке задачи (и её Last-Modified)
+		return nil, Internal(err)
+	}
+	return s.get(ctx, taskID, c.ID) // с автором
+}
+
+func (s *CommentService) List(ctx context.Context, taskID uint, parentID *uint, cursor string, limit int) (*CommentPage, error) { // страница комментариев
+	if limit <= 0 { // дефолт
+		limit = 50
+	}
+	if limit > 100 {
+		return nil, Validation(map[string]string{"limit": "must be 1..100"})
+	}
+	var afterID uint
+	if cursor != "" {
+		id, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		afterID = id
+	}
+	if _, err := s.requireTask(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	items, err := s.repo.List(ctx, taskID, parentID, afterID, limit+1) // +1 — есть ли следующая страница
+	if err != nil {
+		return nil, Internal(err)
+	}
+	page := &CommentPage{Items: items}
+	if len(items) > limit { // есть ещё
+		page.Items = items[:limit]
+		page.NextCursor = encodeCursor(page.Items[limit-1].ID)
+	}
+	return page, nil
+}
+
+func (s *CommentService) Update(ctx context.Context, taskID, id uint, body string) (*types.Comment, error) { // правка (только автор)
+	body, err := normalizeCommentBody(body)
+	if err != nil {
+		return nil, err
+	}
+	c, err := s.authorizeAuthor(ctx, taskID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateBody(ctx, c.ID, body, time.Now()); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, NotFound(nil)
+		}
+		return nil, Internal(err)
+	}
+	return s.get(ctx, taskID, id)
+}
+
+func (s *CommentService) Delete(ctx context.Context, taskID, id uint) error { // удалить (только автор)
+	c, err := s.authorizeAuthor(ctx, taskID, id)
+	if err != nil {
+		return err
+	}
+
+	replies, err := s.repo.HasReplies(ctx, c.ID)
+	if err != nil {
+		return Internal(err)
+	}
+	if replies { // ветку не рвём — оставляем заглушку
+		err = s.repo.Tombstone(ctx, c.ID, time.Now())
+	} else {
+		err = s.repo.Delete(ctx, c.ID)
+	}
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return NotFound(nil)
+		}
+		return Internal(err)
+	}
+
+	if c.ParentID != nil && !replies { // последний ответ под заглушкой — убираем и её
+		parent, err := s.repo.GetByID(ctx, taskID, *c.ParentID)
+		if err == nil && parent.DeletedAt != nil {
+			if more, err := s.repo.HasReplies(ctx, parent.ID); err == nil && !more {
+				_ = s.repo.Delete(ctx, parent.ID) // не страшно, если не вышло
+			}
+		}
+	}
+	if err := s.tasks.Touch(ctx, taskID); err != nil { // comment_count в карточке задачи (и её Last-Modified)
+		return Internal(err)
+	}
+	return nil
+}
+
+func (s *CommentService) authorizeAuthor(ctx context.Context, taskID, id uint) (*types.Comment, error) { // комментарий виден и принадлежит вызывающему
+	actor, err := s.requireTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	c, err := s.repo.GetByID(ctx, taskID, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, NotFound(nil)
+		}
+		return nil, Internal(err)
+	}
+	if c.DeletedAt != nil { // заглушку не трогаем
+		return nil, NotFound(nil)
+	}
+	if c.AuthorID != actor.UserID { // чужой — только читать
+		return nil, Forbidden(map[string]string{"comment": "only the author can change it"})
+	}
+	return c, nil
+}
+
+func (s *CommentService) get(ctx context.Context, taskID, id uint) (*types.Comment, error) { // перечитать с автором
+	c, err := s.repo.GetByID(ctx, taskID, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, NotFound(nil)
+		}
+		return nil, Internal(err)
+	}
+	return c, nil
+}
@@ -0,0 +1,213 @@
+package service // сервисный слой
+
+import (
+	"context" // ctx
+	"errors"  // errors.Is
+	"regexp"  // ключ
+	"slices"  // Contains
+	"strings" // TrimSpace
+
+	"task-tracker/internal/domain/repository" // repo интерфейсы + ошибки
+	"task-tracker/internal/domain/types"      // модели
+)
+
+const ( // ограничения пользовательских полей
+	maxCustomFields  = 50      // полей в пространстве
+	maxFieldOptions  = 100     // вариантов у select
+	maxFieldTextLen  = 1000    // значение text
+	maxFieldNameLen  = 100     // подпись поля
+	maxFieldOptLen   = 100     // один вариант
+	maxFieldKeyLen   = 40      // ключ
+	fieldKeyFallback = "field" // префикс ключа, если из названия ничего не вышло
+)
+
+var fieldKeyRe = regexp.MustCompile(`^[a-z][a-z0-9_]*$`) // customer, env, due_review
+
+type CustomFieldService struct { // сервис определений пользовательских полей
+	repo       repository.CustomFieldRepository // поля
+	workspaces repository.WorkspaceRepository   // роли в пространствах
+}
+
+func NewCustomFieldService(repo repository.CustomFieldRepository, workspaces repository.WorkspaceRepository) *CustomFieldService { // конструктор
+	return &CustomFieldService{repo: repo, workspaces: workspaces} // сохранить зависимости
+}
+
+func fieldKeyFromName(name string) string { // "Customer name" -> "customer_name"
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case b.Len() > 0 && !strings.HasSuffix(b.String(), "_"):
+			b.WriteByte('_')
+		}
+	}
+	key := strings.TrimRight(b.String(), "_")
+	if key == "" || key[0] < 'a' { // кириллица / цифра в начале
+		key = fieldKeyFallback + "_" + key
+	}
+	return strings.TrimRight(key[:min(len(key), maxFieldKeyLen)], "_")
+}
+
+func normalizeFieldOptions(options []string) ([]string, error) { // trim, без пустых и дублей
+	if len(options) > maxFieldOptions {
+		return nil, Validation(map[string]string{"options": "at most 100 options"})
+	}
+	out := make([]string, 0, len(options))
+	for _, o := range options {
+		o = strings.TrimSpace(o)
+		if o == "" || len(o) > maxFieldOptLen {
+			return nil, Validation(map[string]string{"options": "each option must be 1..100 chars"})
+		}
+		if slices.Contains(out, o) {
+			return nil, Validation(map[string]string{"options": "duplicate option " + o})
+		}
+		out = append(out, o)
+	}
+	return out, nil
+}
+
+type CustomFieldInput struct { // определение поля
+	Key     string   // ключ в API ("" = из названия)
+	Name    string   // подпись
+	Type    string   // FieldTypes
+	Options []string // варианты select / multi_select
+}
+
+func (s *CustomFieldService) Create(ctx context.Context, workspaceID uint, in CustomFieldInput) (*types.CustomField, error) { // новое поле (admin+)
+	name := strings.TrimSpace(in.Name)
+	if name == "" || len(name) > maxFieldNameLen {
+		return nil, Validation(map[string]string{"name": "must be 1..100 chars"})
+	}
+	key := strings.TrimSpace(in.Key)
+	if key == "" { // дефолт — из названия
+		key = fieldKeyFromName(name)
+	}
+	if len(key) > maxFieldKeyLen || !fieldKeyRe.MatchString(key) {
+		return nil, Validation(map[string]string{"key": "must match [a-z][a-z0-9_]*, up to 40 chars"})
+	}
+	if !slices.Contains(types.FieldTypes, in.Type) {
+		return nil, Validation(map[string]string{"type": "must be " + strings.Join(types.FieldTypes, "|")})
+	}
+	f := &types.CustomField{WorkspaceID: workspaceID, Key: key, Name: name, Type: in.Type, Options: []string{}}
+	if f.HasOptions() {
+		options, err := normalizeFieldOptions(in.Options)
+		if err != nil {
+			return nil, err
+		}
+		if len(options) == 0 {
+			return nil, Validation(map[string]string{"options": "required for select fields"})
+		}
+		f.Options = options
+	} else if len(in.Options) > 0 {
+		return nil, Validation(map[string]string{"options": "only select fields have options"})
+	}
+
+	if err := s.requireAdmin(ctx, workspaceID); err != nil {
+		return nil, err
+	}
+	n, err := s.repo.Count(ctx, workspaceID)
+	if err != nil {
+		return nil, Internal(err)
+	}
+	if n >= maxCustomFields {
+		return nil, Validation(map[string]string{"fields": "at most 50 custom fields per workspace"})
+	}
+	if err := s.repo.Create(ctx, f); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, Conflict(map[string]string{"key": "already exists"})
+		}
+		return nil, Internal(err)
+	}
+	return f, nil
+}
+
+func (s *CustomFieldService) List(ctx context.Context, workspaceID uint) ([]types.CustomField, error) { // поля пространства (любой участник)
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireWorkspaceRole(ctx, s.workspaces, actor, workspaceID, types.WorkspaceRoleViewer); err != nil {
+		return nil, err
+	}
+	list, err := s.repo.List(ctx, workspaceID)
+	if err != nil {
+		return nil, Internal(err)
+	}
+	return list, nil
+}
+
+func (s *CustomFieldService) Update(ctx context.Context, workspaceID, id uint, name *string, options []string) (*types.CustomField, error) { // переименовать / сменить варианты (admin+)
+	if name == nil && options == nil { // нечего менять
+		return nil, Validation(map[string]string{"body": "nothing to update"})
+	}
+	if err := s.requireAdmin(ctx, workspaceID); err != nil {
+		return nil, err
+	}
+	f, err := s.repo.GetByID(ctx, workspaceID, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, NotFound(nil)
+		}
+		return nil, Internal(err)
+	}
+
+	if name != nil {
+		n := strings.TrimSpace(*name)
+		if n == "" || len(n) > maxFieldNameLen {
+			return nil, Validation(map[string]string{"name": "must be 1..100 chars"})
+		}
+		f.Name = n
+	}
+	if options != nil {
+		if !f.HasOptions() {
+			return nil, Validation(map[string]string{"options": "only select fields have options"})
+		}
+		opts, err := normalizeFieldOptions(options)
+		if err != nil {
+			return nil, err
+		}
+		if len(opts) == 0 {
+			return nil, Validation(map[string]string{"options": "required for select fields"})
+		}
+		for _, old := range f.Options { // выбранный у задач вариант не теряем молча
+			if slices.Contains(opts, old) {
+				continue
+			}
+			used, err := s.repo.OptionInUse(ctx, f.ID, old)
+			if err != nil {
+				return nil, Internal(err)
+			}
+			if used {
+				return nil, Conflict(map[string]string{"options": "option " + old + " is used by tasks"})
+			}
+		}
+		f.Options = opts
+	}
+
+	if err := s.repo.Update(ctx, f); err != nil {
+		return nil, Internal(err)
+	}
+	return f, nil
+}
+
+func (s *CustomFieldService) Delete(ctx context.Context, workspaceID, id uint) error { // удалить поле вместе со значениями (admin+)
+	if err := s.requireAdmin(ctx, workspaceID); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, workspaceID, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return NotFound(nil)
+		}
+		return Internal(err)
+	}
+	return nil
+}
+
+func (s *CustomFieldService) requireAdmin(ctx context.Context, workspaceID uint) error { // поля настраивает admin+
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return err
+	}
+	return requireWorkspaceRole(ctx, s.workspaces, actor, workspaceID, types.WorkspaceRoleAdmin)
+}
@@ -0,0 +1,88 @@
+package service // сервисный слой
+
+import (
+	"context" // ctx
+	"errors"  // errors.Is
+	"time"    // неделя
+
+	"task-tracker/internal/domain/repository" // repo интерфейсы + ошибки
+	"task-tracker/internal/domain/types"      // модели
+)
+
+func (s *TaskService) Aggregate(ctx context.Context, filter types.TaskFilter) (*types.TaskAggregate, error) { // count и суммы оценок по фильтру
+	actor, err := requireActor(ctx) // только видимые задачи
+	if err != nil {
+		return nil, err
+	}
+	if err := s.resolveFilter(ctx, actor, &filter); err != nil {
+		return nil, err
+	}
+	agg, err := s.repo.Aggregate(ctx, actor.Scope(), filter)
+	if err != nil {
+		return nil, Internal(err)
+	}
+	return agg, nil
+}
+
+func weekStart(t time.Time) time.Time { // понедельник 00:00 UTC недели t
+	t = t.UTC().Truncate(24 * time.Hour)
+	offset := (int(t.Weekday()) + 6) % 7 // Mon=0 .. Sun=6
+	return t.AddDate(0, 0, -offset)
+}
+
+func (s *TaskService) Capacity(ctx context.Context, workspaceID uint, week *time.Time) (*types.CapacityReport, error) { // ёмкость участников против назначенных оценок
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireWorkspaceRole(ctx, s.workspaces, actor, workspaceID, types.WorkspaceRoleViewer); err != nil {
+		return nil, err
+	}
+	ws, err := s.workspaces.GetByID(ctx, workspaceID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, NotFound(nil)
+		}
+		return nil, Internal(err)
+	}
+	members, err := s.workspaces.ListMembers(ctx, workspaceID)
+	if err != nil {
+		return nil, Internal(err)
+	}
+
+	report := &types.CapacityReport{WorkspaceID: ws.ID, Unit: ws.EstimateUnit, Members: []types.CapacityRow{}}
+	var from, to *time.Time // nil = все открытые задачи
+	if week != nil {        // только задачи со сроком на этой неделе
+		start := weekStart(*week)
+		end := start.AddDate(0, 0, 7)
+		from, to = &start, &end
+		report.WeekStart = &start
+	}
+	loads, err := s.repo.AssignedEstimates(ctx, workspaceID, from, to)
+	if err != nil {
+		return nil, Internal(err)
+	}
+	byUser := make(map[uint]types.AssignedEstimate, len(loads))
+	for _, l := range loads {
+		byUser[l.UserID] = l
+	}
+
+	for _, m := range members {
+		if types.WorkspaceRoleRank(m.Role) < types.WorkspaceRoleRank(types.WorkspaceRoleMember) { // viewer задачи не получает
+			continue
+		}
+		capacity := ws.WeeklyCapacity // своя ёмкость или пространства
+		if m.WeeklyCapacity != nil {
+			capacity = *m.WeeklyCapacity
+		}
+		load := byUser[m.UserID]
+		report.Members = append(report.Members, types.CapacityRow{
+			UserID:   m.UserID,
+			Email:    m.User.Email,
+			Capacity: capacity,
+			Tasks:    load.Tasks,
+			Assigned: load.Estimate,
+		})
+	}
+	return report, nil
+}
@@ -0,0 +1,152 @@
+package service // сервисный слой
+
+import (
+	"context" // ctx
+	"errors"  // errors.Is
+	"regexp"  // цвет
+	"strings" // TrimSpace
+
+	"task-tracker/internal/domain/repository" // repo интерфейсы + ошибки
+	"task-tracker/internal/domain/types"      // модели
+)
+
+var labelColorRe = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`) // #rrggbb
+
+type LabelService struct { // сервис меток
+	repo       repository.LabelRepository     // метки
+	workspaces repository.WorkspaceRepository // роли в пространствах
+}
+
+func NewLabelService(repo repository.LabelRepository, workspaces repository.WorkspaceRepository) *LabelService { // конструктор
+	return &LabelService{repo: repo, workspaces: workspaces} // сохранить зависимости
+}
+
+func normalizeLabelName(name string) (string, error) { // trim + длина
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > 50 {
+		return "", Validation(map[string]string{"name": "must be 1..50 chars"})
+	}
+	return name, nil
+}
+
+func normalizeLabelColor(color string) (string, error) { // #rrggbb в нижнем регистре
+	if !labelColorRe.MatchString(color) {
+		return "", Validation(map[string]string{"color": "must be #rrggbb"})
+	}
+	return strings.ToLower(color), nil
+}
+
+func (s *LabelService) Create(ctx context.Context, workspaceID *uint, name, color string) (*types.Label, error) { // создать метку
+	actor, err := requireActor(ctx) // личная — вызывающего
+	if err != nil {
+		return nil, err
+	}
+	if name, err = normalizeLabelName(name); err != nil {
+		return nil, err
+	}
+	if color == "" { // дефолт
+		color = types.DefaultLabelColor
+	}
+	if color, err = normalizeLabelColor(color); err != nil {
+		return nil, err
+	}
+	if workspaceID != nil { // метка команды — нужна роль member+
+		if err := requireWorkspaceRole(ctx, s.workspaces, actor, *workspaceID, types.WorkspaceRoleMember); err != nil {
+			return nil, err
+		}
+	}
+
+	label := &types.Label{UserID: actor.UserID, WorkspaceID: workspaceID, Name: name, Color: color} // собираем модель
+	if err := s.repo.Create(ctx, label); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) { // имя занято
+			return nil, Conflict(map[string]string{"name": "already exists"})
+		}
+		return nil, Internal(err)
+	}
+	return label, nil // ok
+}
+
+func (s *LabelService) List(ctx context.Context, workspaceID *uint) ([]types.Label, error) { // доступные метки
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if workspaceID != nil { // чужое пространство = not_found
+		if err := requireWorkspaceRole(ctx, s.workspaces, actor, *workspaceID, types.WorkspaceRoleViewer); err != nil {
+			return nil, err
+		}
+	}
+	labels, err := s.repo.List(ctx, actor.Scope(), workspaceID)
+	if err != nil {
+		return nil, Internal(err)
+	}
+	return labels, nil
+}
+
+func (s *LabelService) Update(ctx context.Context, id uint, name, color *string) (*types.Label, error) { // переименовать/перекрасить
+	if name == nil && color == nil { // нечего менять
+		return nil, Validation(map[string]string{"body": "nothing to update"})
+	}
+	if name != nil {
+		n, err := normalizeLabelName(*name)
+		if err != nil {
+			return nil, err
+		}
+		name = &n
+	}
+	if color != nil {
+		c, err := normalizeLabelColor(*color)
+		if err != nil {
+			return nil, err
+		}
+		color = &c
+	}
+	if _, err := s.authorizeWrite(ctx, id); err != nil {
+		return nil, err
+	}
+
+	label, err := s.repo.Update(ctx, id, name, color)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return nil, NotFound(nil)
+		case errors.Is(err, repository.ErrDuplicate): // имя занято
+			return nil, Conflict(map[string]string{"name": "already exists"})
+		}
+		return nil, Internal(err)
+	}
+	return label, nil
+}
+
+func (s *LabelService) Delete(ctx context.Context, id uint) error { // удалить метку (снимается со всех задач)
+	if _, err := s.authorizeWrite(ctx, id); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return NotFound(nil)
+		}
+		return Internal(err)
+	}
+	return nil
+}
+
+func (s *LabelService) authorizeWrite(ctx context.Context, id uint) (*types.Label, error) { // может ли вызывающий менять метку
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	label, err := s.repo.GetByID(ctx, actor.Scope(), id) // чужая = not_found
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, NotFound(nil)
+		}
+		return nil, Internal(err)
+	}
+	if label.WorkspaceID != nil { // viewer только читает
+		if err := requireWorkspaceRole(ctx, s.workspaces, actor, *label.WorkspaceID, types.WorkspaceRoleMember); err != nil {
+			return nil, err
+		}
+	}
+	return label, nil
+}
@@ -0,0 +1,182 @@
+package service // сервисный слой
+
+import (
+	"context" // ctx
+	"errors"  // errors.Is
+	"strings" // TrimSpace/ToUpper
+	"time"    // due_at
+
+	"task-tracker/internal/domain/repository" // repo интерфейсы + ошибки
+	"task-tracker/internal/domain/types"      // модели
+
+	"github.com/teambition/rrule-go" // RFC 5545 RRULE
+)
+
+const ( // ограничения повторяющихся задач
+	maxRecurrenceLen = 500 // длина RRULE
+	maxOccurrences   = 50  // вхождений в превью
+)
+
+func normalizeRecurrence(s string) (string, error) { // "RRULE:FREQ=weekly;byday=MO" -> "FREQ=WEEKLY;BYDAY=MO"
+	s = strings.TrimSpace(s)
+	if len(s) > maxRecurrenceLen {
+		return "", Validation(map[string]string{"recurrence": "too long"})
+	}
+	s = strings.ToUpper(s)
+	s = strings.TrimPrefix(s, "RRULE:")
+	if strings.ContainsAny(s, "\r\n") { // DTSTART задаёт due_at, а не клиент
+		return "", Validation(map[string]string{"recurrence": "must be a single RRULE line without DTSTART"})
+	}
+	opt, err := rrule.StrToROption(s)
+	if err != nil {
+		return "", Validation(map[string]string{"recurrence": err.Error()})
+	}
+	switch opt.Freq {
+	case rrule.DAILY, rrule.WEEKLY, rrule.MONTHLY, rrule.YEARLY:
+	default: // чаще раза в день задачи не повторяются
+		return "", Validation(map[string]string{"recurrence": "FREQ must be DAILY|WEEKLY|MONTHLY|YEARLY"})
+	}
+	return opt.RRuleString(), nil
+}
+
+func checkRecurrencePatch(current *types.Task, patch *types.TaskPatch) error { // RRULE нормализован, у серии есть срок
+	due := current.DueAt // срок после патча
+	if patch.DueAt != nil {
+		due = patch.DueAt
+	}
+	if patch.ClearDueAt {
+		due = nil
+	}
+	if patch.Recurrence != nil && strings.TrimSpace(*patch.Recurrence) == "" { // "" = то же, что null
+		patch.Recurrence, patch.ClearRecurrence = nil, true
+	}
+
+	switch {
+	case patch.ClearRecurrence:
+		patch.Recurrence = nil
+	case patch.Recurrence != nil: // новое правило — серия начинается заново с текущего срока
+		if due == nil {
+			return Validation(map[string]string{"due_at": "required for recurring tasks"})
+		}
+		rule, err := normalizeRecurrence(*patch.Recurrence)
+		if err != nil {
+			return err
+		}
+		patch.Recurrence, patch.RecurrenceStart = &rule, due
+	case current.Recurrence != "" && due == nil: // срок снимают, а правило остаётся
+		return Validation(map[string]string{"due_at": "required for recurring tasks; clear recurrence first"})
+	}
+	return nil
+}
+
+func recurrenceRule(t *types.Task) (*rrule.RRule, error) { // правило серии с DTSTART
+	opt, err := rrule.StrToROption(t.Recurrence)
+	if err != nil {
+		return nil, err
+	}
+	opt.Dtstart = *t.DueAt
+	if t.RecurrenceStart != nil { // начало серии, а не текущего вхождения
+		opt.Dtstart = *t.RecurrenceStart
+	}
+	return rrule.NewRRule(*opt)
+}
+
+func (s *TaskService) spawnOccurrence(ctx context.Context, done *types.Task) (*uint, error) { // создать следующее вхождение закрытой задачи
+	if done.Recurrence == "" || done.DueAt == nil || done.NextOccurrenceID != nil { // не серия / уже создано
+		return done.NextOccurrenceID, nil
+	}
+	rule, err := recurrenceRule(done)
+	if err != nil {
+		return nil, Internal(err)
+	}
+	due := rule.After(*done.DueAt, false) // следующая дата по правилу
+	if due.IsZero() {                     // серия закончилась (COUNT/UNTIL)
+		return nil, nil
+	}
+	wf, err := s.workflowFor(ctx, done.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	st, _ := wf.Status(wf.Initial)
+
+	next := &types.Task{ // копия без состояния выполнения
+		UserID:          done.UserID,
+		WorkspaceID:     done.WorkspaceID,
+		ParentID:        done.ParentID,
+		Title:           done.Title,
+		Description:     done.Description,
+		Priority:        done.Priority,
+		Estimate:        done.Estimate,
+		DueAt:           &due,
+		Status:          st.Key,
+		Done:            st.Done,
+		Recurrence:      done.Recurrence,
+		RecurrenceStart: done.RecurrenceStart,
+	}
+	for _, l := range done.Labels {
+		next.Labels = append(next.Labels, types.Label{ID: l.ID})
+	}
+	for _, u := range done.Assignees {
+		next.Assignees = append(next.Assignees, types.User{ID: u.ID})
+	}
+	for _, u := range done.Watchers {
+		next.Watchers = append(next.Watchers, types.User{ID: u.ID})
+	}
+	for _, v := range done.Fields { // значения полей (определение не вставляется, нужно только истории)
+		next.Fields = append(next.Fields, types.TaskFieldValue{
+			Field:       v.Field,
+			FieldID:     v.FieldID,
+			TextValue:   v.TextValue,
+			NumberValue: v.NumberValue,
+			DateValue:   v.DateValue,
+			UserValue:   v.UserValue,
+			MultiValue:  v.MultiValue,
+		})
+	}
+	for _, item := range done.Checklist { // пункты заново не отмечены
+		next.Checklist = append(next.Checklist, types.ChecklistItem{Position: item.Position, Title: item.Title})
+	}
+
+	if err := s.repo.CreateOccurrence(ctx, done.ID, next); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) { // параллельный запрос успел раньше
+			return nil, nil
+		}
+		return nil, Internal(err)
+	}
+	if actor, err := requireActor(ctx); err == nil { // вхождение создал тот, кто закрыл предыдущее
+		if err := s.record(ctx, actor, types.RevisionCreate, nil, next, nil); err != nil {
+			return nil, err
+		}
+	}
+	return &next.ID, nil
+}
+
+func (s *TaskService) Occurrences(ctx context.Context, id uint, limit int) (*types.Task, []time.Time, error) { // ближайшие сроки серии после текущего
+	if limit <= 0 { // дефолт
+		limit = 10
+	}
+	if limit > maxOccurrences {
+		return nil, nil, Validation(map[string]string{"limit": "must be 1..50"})
+	}
+	task, err := s.GetByID(ctx, id) // чужая = not_found
+	if err != nil {
+		return nil, nil, err
+	}
+	if task.Recurrence == "" || task.DueAt == nil {
+		return nil, nil, Validation(map[string]string{"recurrence": "task is not recurring"})
+	}
+	rule, err := recurrenceRule(task)
+	if err != nil {
+		return nil, nil, Internal(err)
+	}
+
+	dates := make([]time.Time, 0, limit) // всегда массив, не null
+	for at := *task.DueAt; len(dates) < limit; {
+		at = rule.After(at, false)
+		if at.IsZero() { // серия закончилась
+			break
+		}
+		dates = append(dates, at)
+	}
+	return task, dates, nil
+}
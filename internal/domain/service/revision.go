@@ -0,0 +1,259 @@
+package service // сервисный слой
+
+import (
+	"context"       // ctx
+	"encoding/json" // нормализация значений
+	"errors"        // errors.Is
+	"maps"          // Keys
+	"reflect"       // DeepEqual
+	"slices"        // Sorted
+	"strings"       // HasPrefix
+
+	"task-tracker/internal/domain/repository" // repo интерфейсы + ошибки
+	"task-tracker/internal/domain/types"      // модели
+)
+
+const customFieldPrefix = "custom_fields." // имя изменения пользовательского поля
+
+var revisionFieldOrder = []string{"title", "description", "priority", "status", "done", "due_at", "estimate", "parent_id", "recurrence", "archived", "label_ids", "assignee_ids", "watcher_ids", "blocked_by_ids", "checklist"} // порядок полей в diff
+
+func snapshotOf(t *types.Task) types.TaskSnapshot { // текущее состояние задачи для истории
+	snap := types.TaskSnapshot{
+		Title:       t.Title,
+		Description: t.Description,
+		Priority:    t.Priority,
+		Status:      t.Status,
+		Done:        t.Done,
+		DueAt:       t.DueAt,
+		Estimate:    t.Estimate,
+		ParentID:    t.ParentID,
+		Recurrence:  t.Recurrence,
+		Archived:    t.ArchivedAt != nil,
+	}
+	if snap.DueAt != nil { // одна зона — иначе тот же срок выглядит изменённым
+		due := snap.DueAt.UTC()
+		snap.DueAt = &due
+	}
+	for _, l := range t.Labels {
+		snap.LabelIDs = append(snap.LabelIDs, l.ID)
+	}
+	for _, u := range t.Assignees {
+		snap.AssigneeIDs = append(snap.AssigneeIDs, u.ID)
+	}
+	for _, u := range t.Watchers {
+		snap.WatcherIDs = append(snap.WatcherIDs, u.ID)
+	}
+	for _, ref := range t.BlockedBy {
+		snap.BlockedByIDs = append(snap.BlockedByIDs, ref.ID)
+	}
+	slices.Sort(snap.LabelIDs) // порядок загрузки не должен выглядеть изменением
+	slices.Sort(snap.AssigneeIDs)
+	slices.Sort(snap.WatcherIDs)
+	slices.Sort(snap.BlockedByIDs)
+	for _, item := range t.Checklist { // порядок пунктов — часть состояния
+		snap.Checklist = append(snap.Checklist, types.ChecklistSnapshot{ID: item.ID, Title: item.Title, Done: item.Done})
+	}
+	for _, v := range t.Fields {
+		if v.Field == nil {
+			continue
+		}
+		if snap.CustomFields == nil {
+			snap.CustomFields = make(map[string]any, len(t.Fields))
+		}
+		snap.CustomFields[v.Field.Key] = v.Value(v.Field.Type)
+	}
+	return snap
+}
+
+func snapshotValues(snap *types.TaskSnapshot) map[string]any { // плоские значения в JSON-виде (сравнимы и после чтения из БД)
+	values := map[string]any{}
+	if snap == nil { // до создания — ничего
+		return values
+	}
+	flat := map[string]any{
+		"title":          snap.Title,
+		"description":    snap.Description,
+		"priority":       snap.Priority,
+		"status":         snap.Status,
+		"done":           snap.Done,
+		"due_at":         snap.DueAt,
+		"estimate":       snap.Estimate,
+		"parent_id":      snap.ParentID,
+		"recurrence":     snap.Recurrence,
+		"archived":       snap.Archived,
+		"label_ids":      snap.LabelIDs,
+		"assignee_ids":   snap.AssigneeIDs,
+		"watcher_ids":    snap.WatcherIDs,
+		"blocked_by_ids": snap.BlockedByIDs,
+		"checklist":      snap.Checklist,
+	}
+	for k, v := range snap.CustomFields {
+		flat[customFieldPrefix+k] = v
+	}
+	raw, _ := json.Marshal(flat)     // только JSON-совместимые типы
+	_ = json.Unmarshal(raw, &values) // uint -> float64, []string -> []any
+	return values
+}
+
+func diffSnapshots(before, after *types.TaskSnapshot) []types.FieldChange { // поле за полем: было -> стало
+	b, a := snapshotValues(before), snapshotValues(after)
+	keys := slices.Clone(revisionFieldOrder)
+	var custom []string
+	for k := range maps.Keys(b) {
+		if strings.HasPrefix(k, customFieldPrefix) {
+			custom = append(custom, k)
+		}
+	}
+	for k := range maps.Keys(a) {
+		if strings.HasPrefix(k, customFieldPrefix) && !slices.Contains(custom, k) {
+			custom = append(custom, k)
+		}
+	}
+	slices.Sort(custom)
+
+	changes := []types.FieldChange{} // всегда массив, не null
+	for _, k := range append(keys, custom...) {
+		if before != nil && reflect.DeepEqual(b[k], a[k]) {
+			continue
+		}
+		if before == nil && (a[k] == nil || a[k] == "" || a[k] == false) { // при создании пустые поля не перечисляем
+			continue
+		}
+		changes = append(changes, types.FieldChange{Field: k, Before: b[k], After: a[k]})
+	}
+	return changes
+}
+
+func (s *TaskService) record(ctx context.Context, actor Actor, action string, before, after *types.Task, revertedFrom *uint) error { // записать ревизию
+	var prev *types.TaskSnapshot
+	if before != nil {
+		snap := snapshotOf(before)
+		prev = &snap
+	}
+	current := after
+	if current == nil { // удаление — состояние на момент удаления
+		current = before
+	}
+	snap := snapshotOf(current)
+
+	changes := []types.FieldChange{}
+	if action != types.RevisionDelete {
+		changes = diffSnapshots(prev, &snap)
+	}
+	if action == types.RevisionUpdate && len(changes) == 0 { // PATCH без фактических изменений
+		return nil
+	}
+
+	var userID *uint // nil — фоновая задача (автоархивация)
+	if actor.UserID != 0 {
+		id := actor.UserID
+		userID = &id
+	}
+	rev := &types.TaskRevision{TaskID: current.ID, UserID: userID, Action: action, RevertedFrom: revertedFrom, Changes: changes, Snapshot: snap}
+	if err := s.revisions.Create(ctx, rev); err != nil {
+		return Internal(err)
+	}
+	return nil
+}
+
+func (s *TaskService) History(ctx context.Context, id uint, limit, offset int) ([]types.TaskRevision, error) { // история изменений задачи
+	if limit <= 0 { // дефолт
+		limit = 50
+	}
+	if limit > 200 || offset < 0 {
+		return nil, Validation(map[string]string{
+			"limit":  "must be 1..200",
+			"offset": "must be >= 0",
+		})
+	}
+	if _, err := s.GetByID(ctx, id); err != nil { // чужая = not_found
+		return nil, err
+	}
+	list, err := s.revisions.ListByTask(ctx, id, limit, offset)
+	if err != nil {
+		return nil, Internal(err)
+	}
+	return list, nil
+}
+
+func (s *TaskService) Revert(ctx context.Context, id, revisionID uint, opts UpdateTaskOptions) (*types.Task, error) { // вернуть поля задачи к состоянию ревизии
+	current, err := s.GetByID(ctx, id) // чужая = not_found
+	if err != nil {
+		return nil, err
+	}
+	rev, err := s.revisions.GetByID(ctx, id, revisionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, NotFound(map[string]string{"revision": "not found"})
+		}
+		return nil, Internal(err)
+	}
+
+	patch, err := s.revertPatch(ctx, current, &rev.Snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if patch.Empty() { // уже в этом состоянии
+		return current, nil
+	}
+	return s.update(ctx, id, patch, opts, &rev.ID)
+}
+
+func (s *TaskService) revertPatch(ctx context.Context, current *types.Task, target *types.TaskSnapshot) (types.TaskPatch, error) { // изменения, возвращающие задачу к target
+	var patch types.TaskPatch
+	now := snapshotOf(current)
+	have, want := snapshotValues(&now), snapshotValues(target)
+	differs := func(k string) bool { return !reflect.DeepEqual(have[k], want[k]) }
+
+	if differs("title") {
+		patch.Title = &target.Title
+	}
+	if differs("description") {
+		patch.Description = &target.Description
+	}
+	if differs("priority") {
+		patch.Priority = &target.Priority
+	}
+	if differs("status") { // done — производное от статуса
+		patch.Status = &target.Status
+	}
+	if differs("due_at") {
+		patch.DueAt, patch.ClearDueAt = target.DueAt, target.DueAt == nil
+	}
+	if differs("estimate") {
+		patch.Estimate, patch.ClearEstimate = target.Estimate, target.Estimate == nil
+	}
+	if differs("parent_id") {
+		patch.ParentID, patch.ClearParentID = target.ParentID, target.ParentID == nil
+	}
+	if differs("recurrence") {
+		patch.Recurrence, patch.ClearRecurrence = &target.Recurrence, target.Recurrence == ""
+	}
+
+	var keys []string // пользовательские поля, которые различаются
+	for k := range maps.Keys(have) {
+		if strings.HasPrefix(k, customFieldPrefix) && differs(k) {
+			keys = append(keys, strings.TrimPrefix(k, customFieldPrefix))
+		}
+	}
+	for k := range maps.Keys(want) {
+		key := strings.TrimPrefix(k, customFieldPrefix)
+		if strings.HasPrefix(k, customFieldPrefix) && differs(k) && !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 || current.WorkspaceID == nil {
+		return patch, nil
+	}
+	defined, err := s.fields.GetByKeys(ctx, *current.WorkspaceID, keys) // удалённые с тех пор поля пропускаем
+	if err != nil {
+		return patch, Internal(err)
+	}
+	for _, f := range defined {
+		if patch.CustomFields == nil {
+			patch.CustomFields = make(map[string]any, len(defined))
+		}
+		patch.CustomFields[f.Key] = want[customFieldPrefix+f.Key] // nil = очистить
+	}
+	return patch, nil
+}
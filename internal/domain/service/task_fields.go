@@ -0,0 +1,235 @@
+package service // сервисный слой
+
+import (
+	"context" // ctx
+	"errors"  // errors.Is
+	"maps"    // Keys
+	"math"    // Trunc
+	"slices"  // Contains
+	"strconv" // значения фильтров
+	"strings" // TrimSpace
+	"time"    // date
+
+	"task-tracker/internal/domain/repository" // repo интерфейсы + ошибки
+	"task-tracker/internal/domain/types"      // модели
+)
+
+var taskSortColumns = []string{"id", "created_at", "updated_at", "due_at", "title", "estimate"} // встроенные колонки ?sort=
+
+func (s *TaskService) loadFields(ctx context.Context, workspaceID uint, keys []string, param string) (map[string]types.CustomField, error) { // ключи -> определения (неизвестный ключ — ошибка)
+	list, err := s.fields.GetByKeys(ctx, workspaceID, keys)
+	if err != nil {
+		return nil, Internal(err)
+	}
+	byKey := make(map[string]types.CustomField, len(list))
+	for _, f := range list {
+		byKey[f.Key] = f
+	}
+	for _, k := range keys {
+		if _, ok := byKey[k]; !ok {
+			return nil, Validation(map[string]string{param + "." + k: "unknown custom field"})
+		}
+	}
+	return byKey, nil
+}
+
+func (s *TaskService) resolveFieldValues(ctx context.Context, workspaceID *uint, raw map[string]any) ([]types.TaskFieldValue, []uint, error) { // значения из API -> строки task_field_values
+	if len(raw) == 0 {
+		return nil, nil, nil
+	}
+	if workspaceID == nil { // поля задаёт пространство
+		return nil, nil, Validation(map[string]string{"custom_fields": "only workspace tasks have custom fields"})
+	}
+	keys := slices.Sorted(maps.Keys(raw))
+	byKey, err := s.loadFields(ctx, *workspaceID, keys, "custom_fields")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var set []types.TaskFieldValue
+	var clearIDs []uint
+	for _, k := range keys {
+		f := byKey[k]
+		v, err := s.parseFieldValue(ctx, &f, raw[k])
+		if err != nil {
+			return nil, nil, err
+		}
+		if v == nil { // null / "" / [] — очистить
+			clearIDs = append(clearIDs, f.ID)
+			continue
+		}
+		set = append(set, *v)
+	}
+	return set, clearIDs, nil
+}
+
+func (s *TaskService) parseFieldValue(ctx context.Context, f *types.CustomField, raw any) (*types.TaskFieldValue, error) { // проверить тип значения (nil = очистить)
+	if raw == nil {
+		return nil, nil
+	}
+	invalid := func(msg string) error { return Validation(map[string]string{"custom_fields." + f.Key: msg}) }
+	v := &types.TaskFieldValue{FieldID: f.ID}
+
+	switch f.Type {
+	case types.FieldTypeText:
+		str, ok := raw.(string)
+		if !ok {
+			return nil, invalid("must be a string")
+		}
+		str = strings.TrimSpace(str)
+		if str == "" {
+			return nil, nil
+		}
+		if len(str) > maxFieldTextLen {
+			return nil, invalid("too long")
+		}
+		v.TextValue = &str
+	case types.FieldTypeNumber:
+		n, ok := raw.(float64)
+		if !ok {
+			return nil, invalid("must be a number")
+		}
+		v.NumberValue = &n
+	case types.FieldTypeDate:
+		str, ok := raw.(string)
+		if !ok {
+			return nil, invalid("must be YYYY-MM-DD")
+		}
+		if str == "" {
+			return nil, nil
+		}
+		d, err := time.Parse(time.DateOnly, str)
+		if err != nil {
+			return nil, invalid("must be YYYY-MM-DD")
+		}
+		v.DateValue = &d
+	case types.FieldTypeSelect:
+		str, ok := raw.(string)
+		if !ok {
+			return nil, invalid("must be one of the options")
+		}
+		if str == "" {
+			return nil, nil
+		}
+		if !slices.Contains(f.Options, str) {
+			return nil, invalid("must be one of: " + strings.Join(f.Options, ", "))
+		}
+		v.TextValue = &str
+	case types.FieldTypeMultiSelect:
+		list, ok := raw.([]any)
+		if !ok {
+			return nil, invalid("must be an array of options")
+		}
+		picked := make([]string, 0, len(list))
+		for _, item := range list {
+			str, ok := item.(string)
+			if !ok || !slices.Contains(f.Options, str) {
+				return nil, invalid("must be an array of: " + strings.Join(f.Options, ", "))
+			}
+			if !slices.Contains(picked, str) { // без дублей
+				picked = append(picked, str)
+			}
+		}
+		if len(picked) == 0 {
+			return nil, nil
+		}
+		v.MultiValue = picked
+	case types.FieldTypeUser:
+		n, ok := raw.(float64)
+		if !ok || n <= 0 || n != math.Trunc(n) {
+			return nil, invalid("must be a user id")
+		}
+		userID := uint(n)
+		if _, err := s.workspaces.GetMember(ctx, f.WorkspaceID, userID); err != nil { // только участники пространства
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, invalid("not a workspace member")
+			}
+			return nil, Internal(err)
+		}
+		v.UserValue = &userID
+	}
+	return v, nil
+}
+
+func (s *TaskService) resolveFilter(ctx context.Context, actor Actor, filter *types.TaskFilter) error { // normalizeTaskFilter + разбор cf[...] / sort
+	if err := normalizeTaskFilter(filter); err != nil {
+		return err
+	}
+
+	sortKey := ""
+	if filter.SortBy != "" {
+		by, desc := strings.CutPrefix(filter.SortBy, "-") // -due_at = по убыванию
+		switch {
+		case strings.HasPrefix(by, "cf."):
+			sortKey = strings.TrimPrefix(by, "cf.")
+			filter.Sort = &types.TaskSort{Desc: desc}
+		case slices.Contains(taskSortColumns, by):
+			filter.Sort = &types.TaskSort{Column: by, Desc: desc}
+		default:
+			return Validation(map[string]string{"sort": "must be " + strings.Join(taskSortColumns, "|") + "|cf.<key>, optionally prefixed with -"})
+		}
+	}
+	if len(filter.FieldEquals) == 0 && sortKey == "" {
+		return nil
+	}
+	if filter.WorkspaceID == nil { // ключи полей уникальны только внутри пространства
+		return Validation(map[string]string{"workspace_id": "required to filter or sort by custom fields"})
+	}
+
+	keys := slices.Sorted(maps.Keys(filter.FieldEquals))
+	if sortKey != "" && !slices.Contains(keys, sortKey) {
+		keys = append(keys, sortKey)
+	}
+	byKey, err := s.loadFields(ctx, *filter.WorkspaceID, keys, "cf")
+	if err != nil {
+		return err
+	}
+	if sortKey != "" {
+		f := byKey[sortKey]
+		if f.Type == types.FieldTypeMultiSelect { // у нескольких значений нет порядка
+			return Validation(map[string]string{"sort": "multi_select fields are not sortable"})
+		}
+		filter.Sort.Field = &f
+	}
+	for _, k := range slices.Sorted(maps.Keys(filter.FieldEquals)) {
+		f := byKey[k]
+		value, err := parseFieldFilter(actor, &f, filter.FieldEquals[k])
+		if err != nil {
+			return err
+		}
+		filter.FieldConds = append(filter.FieldConds, types.FieldCond{Field: f, Value: value})
+	}
+	return nil
+}
+
+func parseFieldFilter(actor Actor, f *types.CustomField, raw string) (any, error) { // ?cf[key]=строка -> значение в типе поля
+	invalid := func(msg string) error { return Validation(map[string]string{"cf." + f.Key: msg}) }
+	switch f.Type {
+	case types.FieldTypeNumber:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, invalid("must be a number")
+		}
+		return n, nil
+	case types.FieldTypeDate:
+		d, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			return nil, invalid("must be YYYY-MM-DD")
+		}
+		return d, nil
+	case types.FieldTypeUser:
+		if raw == "me" {
+			return actor.UserID, nil
+		}
+		id, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil || id == 0 {
+			return nil, invalid("must be me or user id")
+		}
+		return uint(id), nil
+	case types.FieldTypeSelect, types.FieldTypeMultiSelect:
+		if !slices.Contains(f.Options, raw) {
+			return nil, invalid("must be one of: " + strings.Join(f.Options, ", "))
+		}
+	}
+	return raw, nil
+}
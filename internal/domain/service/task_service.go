@@ -3,18 +3,52 @@ package service // сервисный слой
 import (
 	"context" // ctx
 	"errors"  // errors.Is
+	"slices"  // Contains
 	"strings" // TrimSpace
+	"time"    // completed_at
 
 	"task-tracker/internal/domain/repository" // repo интерфейс + ошибки
 	"task-tracker/internal/domain/types"      // модели
+	"task-tracker/internal/storage"           // BlobStore
 )
 
 type TaskService struct { // сервис задач
-	repo repository.TaskRepository // зависимость
+	repo       repository.TaskRepository         // зависимость
+	users      repository.UserRepository         // проверка владельца
+	workspaces repository.WorkspaceRepository    // роли в пространствах
+	workflows  repository.WorkflowRepository     // процессы статусов
+	labels     repository.LabelRepository        // метки
+	checklists repository.ChecklistRepository    // чек-листы
+	fields     repository.CustomFieldRepository  // пользовательские поля
+	revisions  repository.TaskRevisionRepository // история изменений
+	tx         repository.Transactor             // общая транзакция (bulk)
+	blobs      storage.BlobStore                 // файлы вложений (очистка корзины)
+
+	autoCompleteChecklist bool          // последний отмеченный пункт закрывает задачу
+	trashRetention        time.Duration // сколько задача лежит в корзине до окончательного удаления
+	autoArchiveAfter      time.Duration // выполненные задачи старше уходят в архив (0 = выключено)
+}
+
+type TaskOption func(*TaskService) // настройка TaskService
+
+func WithChecklistAutoComplete(on bool) TaskOption { // закрывать задачу, когда отмечен последний пункт чек-листа
+	return func(s *TaskService) { s.autoCompleteChecklist = on }
+}
+
+func WithTrashRetention(d time.Duration) TaskOption { // срок хранения корзины
+	return func(s *TaskService) { s.trashRetention = d }
 }
 
-func NewTaskService(repo repository.TaskRepository) *TaskService { // конструктор
-	return &TaskService{repo: repo} // сохранить repo
+func WithAutoArchiveAfter(d time.Duration) TaskOption { // автоархивация выполненных задач
+	return func(s *TaskService) { s.autoArchiveAfter = d }
+}
+
+func NewTaskService(repo repository.TaskRepository, users repository.UserRepository, workspaces repository.WorkspaceRepository, workflows repository.WorkflowRepository, labels repository.LabelRepository, checklists repository.ChecklistRepository, fields repository.CustomFieldRepository, revisions repository.TaskRevisionRepository, tx repository.Transactor, blobs storage.BlobStore, opts ...TaskOption) *TaskService { // конструктор
+	s := &TaskService{repo: repo, users: users, workspaces: workspaces, workflows: workflows, labels: labels, checklists: checklists, fields: fields, revisions: revisions, tx: tx, blobs: blobs, trashRetention: defaultTrashRetention} // сохранить repo
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *TaskService) Version() string { return "0.1.0" } // версия
@@ -23,28 +57,185 @@ func (s *TaskService) Health(ctx context.Context) error { // healthcheck
 	return s.repo.Ping(ctx) // ping хранилища
 }
 
-func (s *TaskService) Create(ctx context.Context, userID uint, title string) (*types.Task, error) { // создать задачу
-	title = strings.TrimSpace(title) // чистим title
-	if userID == 0 || title == "" {  // базовая валидация
-		return nil, Validation(map[string]string{
-			"user_id": "must be > 0",
-			"title":   "required",
-		}) // ошибка валидации
+type CreateTaskInput struct { // данные для создания задачи
+	UserID      uint       // владелец (0 = текущий пользователь)
+	WorkspaceID *uint      // пространство (nil = личная)
+	Title       string     // заголовок
+	Description string     // описание (markdown)
+	Priority    string     // приоритет ("" = medium)
+	Status      string     // статус процесса ("" = начальный)
+	DueAt       *time.Time // срок (опц.)
+	ParentID    *uint      // родительская задача (опц.)
+	AssigneeIDs []uint     // исполнители (опц.)
+	Recurrence  string     // RRULE ("" = не повторяется, нужен due_at)
+	Estimate    *float64   // оценка в единицах пространства (опц.)
+
+	CustomFields map[string]any // значения пользовательских полей по ключу (опц.)
+}
+
+const maxDescriptionLen = 20000 // ограничение на описание
+
+const maxEstimate = 1000 // оценка одной задачи (points или часы)
+
+func validPriority(p string) bool { return slices.Contains(types.TaskPriorities, p) } // известный приоритет?
+
+func validEstimate(e float64) bool { return e >= 0 && e <= maxEstimate } // разумная оценка?
+
+func (s *TaskService) Create(ctx context.Context, in CreateTaskInput) (*types.Task, error) { // создать задачу
+	actor, err := requireActor(ctx) // кто создаёт
+	if err != nil {
+		return nil, err
+	}
+	userID := in.UserID
+	if userID == 0 { // по умолчанию — себе
+		userID = actor.UserID
+	}
+	if userID != actor.UserID && !actor.IsAdmin() { // чужому — только админ
+		return nil, Forbidden(map[string]string{"user_id": "only admin can create tasks for other users"})
+	}
+
+	title := strings.TrimSpace(in.Title) // чистим title
+	if title == "" {                     // базовая валидация
+		return nil, Validation(map[string]string{"title": "required"}) // ошибка валидации
+	}
+	if len(in.Description) > maxDescriptionLen { // описание не безразмерное
+		return nil, Validation(map[string]string{"description": "too long"})
+	}
+	priority := in.Priority
+	if priority == "" { // дефолт
+		priority = types.PriorityMedium
+	}
+	if !validPriority(priority) {
+		return nil, Validation(map[string]string{"priority": "must be low|medium|high|urgent"})
+	}
+
+	if in.Estimate != nil && !validEstimate(*in.Estimate) {
+		return nil, Validation(map[string]string{"estimate": "must be 0..1000"})
+	}
+	recurrence := ""
+	if strings.TrimSpace(in.Recurrence) != "" { // повторяющаяся — считаем от due_at
+		if in.DueAt == nil {
+			return nil, Validation(map[string]string{"due_at": "required for recurring tasks"})
+		}
+		if recurrence, err = normalizeRecurrence(in.Recurrence); err != nil {
+			return nil, err
+		}
+	}
+
+	exists, err := s.users.Exists(ctx, userID) // владелец должен существовать
+	if err != nil {
+		return nil, Internal(err)
+	}
+	if !exists { // неизвестный user_id
+		return nil, Validation(map[string]string{"user_id": "not found"})
+	}
+
+	if in.WorkspaceID != nil { // задача команды — нужна роль member+
+		if err := requireWorkspaceRole(ctx, s.workspaces, actor, *in.WorkspaceID, types.WorkspaceRoleMember); err != nil {
+			return nil, err
+		}
+	}
+
+	if in.ParentID != nil { // подзадача — в том же пространстве, что и родитель
+		if err := s.checkParent(ctx, actor, 0, userID, in.WorkspaceID, *in.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	var assignees []types.User // исполнители без дублей
+	for _, id := range in.AssigneeIDs {
+		if slices.ContainsFunc(assignees, func(u types.User) bool { return u.ID == id }) {
+			continue
+		}
+		if err := s.checkAssignee(ctx, userID, in.WorkspaceID, id); err != nil {
+			return nil, err
+		}
+		assignees = append(assignees, types.User{ID: id})
+	}
+
+	wf, err := s.workflowFor(ctx, in.WorkspaceID) // процесс пространства
+	if err != nil {
+		return nil, err
+	}
+	status := in.Status
+	if status == "" { // дефолт — начальный статус процесса
+		status = wf.Initial
+	}
+	st, ok := wf.Status(status)
+	if !ok {
+		return nil, Validation(map[string]string{"status": "unknown status " + status})
+	}
+
+	fields, _, err := s.resolveFieldValues(ctx, in.WorkspaceID, in.CustomFields) // null при создании = просто не задавать
+	if err != nil {
+		return nil, err
 	}
 
 	task := &types.Task{ // собираем модель
-		UserID: userID, // владелец
-		Title:  title,  // заголовок
-		Done:   false,  // дефолт
+		UserID:      userID,         // владелец
+		WorkspaceID: in.WorkspaceID, // пространство (nil = личная)
+		Title:       title,          // заголовок
+		Description: in.Description, // описание
+		Priority:    priority,       // приоритет
+		DueAt:       in.DueAt,       // срок
+		Estimate:    in.Estimate,    // оценка
+		ParentID:    in.ParentID,    // родитель
+		Status:      st.Key,         // статус
+		Done:        st.Done,        // производное от статуса
+		Assignees:   assignees,      // исполнители (только связи)
+		Recurrence:  recurrence,     // правило повторения
+		Fields:      fields,         // значения пользовательских полей
+	}
+	if recurrence != "" { // серия начинается с первого срока
+		task.RecurrenceStart = in.DueAt
+	}
+	if task.Done { // сразу выполненная
+		now := time.Now()
+		task.CompletedAt = &now
 	}
 
-	if err := s.repo.Create(ctx, task); err != nil { // записываем в БД
-		return nil, Internal(err) // пробрасываем ошибку
+	err = s.inTx(ctx, func(ctx context.Context) error { // задача и её первая ревизия — вместе
+		if err := s.repo.Create(ctx, task); err != nil { // записываем в БД
+			return Internal(err) // пробрасываем ошибку
+		}
+		if len(assignees) > 0 || len(fields) > 0 { // перечитать — нужны email исполнителей и определения полей
+			var err error
+			if task, err = s.GetByID(ctx, task.ID); err != nil {
+				return err
+			}
+		}
+		return s.record(ctx, actor, types.RevisionCreate, nil, task, nil) // первая ревизия
+	})
+	if err != nil {
+		return nil, err
 	}
 	return task, nil // вернуть созданную
 }
 
-func (s *TaskService) List(ctx context.Context, done *bool, limit, offset int) ([]types.Task, error) { // список задач
+func (s *TaskService) inTx(ctx context.Context, fn func(ctx context.Context) error) error { // fn в транзакции: изменение и его ревизия фиксируются вместе
+	err := s.tx.Transaction(ctx, fn)
+	var appErr *AppError
+	if err != nil && !errors.As(err, &appErr) { // COMMIT не прошёл и т.п.
+		return Internal(err)
+	}
+	return err
+}
+
+func normalizeTaskFilter(filter *types.TaskFilter) error { // дефолты и пределы фильтра
+	switch filter.LabelMatch {
+	case "": // дефолт
+		filter.LabelMatch = types.LabelMatchAny
+	case types.LabelMatchAny, types.LabelMatchAll:
+	default:
+		return Validation(map[string]string{"label_match": "must be any|all"})
+	}
+	if len(filter.Labels) > 20 { // не даём раздувать запрос
+		return Validation(map[string]string{"label": "at most 20 labels"})
+	}
+	return nil
+}
+
+func (s *TaskService) List(ctx context.Context, filter types.TaskFilter, limit, offset int) ([]types.Task, error) { // список задач
 	// базовые правила для API
 	if limit <= 0 { // дефолт
 		limit = 20
@@ -55,16 +246,43 @@ func (s *TaskService) List(ctx context.Context, done *bool, limit, offset int) (
 			"offset": "must be >= 0",
 		})
 	}
-	tasks, err := s.repo.List(ctx, done, limit, offset)
+	actor, err := requireActor(ctx) // только свои задачи
+	if err != nil {
+		return nil, err
+	}
+	if err := s.resolveFilter(ctx, actor, &filter); err != nil {
+		return nil, err
+	}
+	tasks, err := s.repo.List(ctx, actor.Scope(), filter, limit, offset)
 	if err != nil {
 		return nil, Internal(err)
 	}
 	return tasks, nil
 }
 
+func (s *TaskService) ListLastModified(ctx context.Context, workspaceID *uint) (time.Time, error) { // Last-Modified списка: любое изменение видимых задач
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if workspaceID == nil { // сквозной список меняется и от вступления/выхода из пространств, а следа в задачах это не оставляет — только ETag
+		return time.Time{}, nil
+	}
+	// берём всё видимое, а не только выборку: задача, ушедшая из фильтра (сменила статус, удалена), тоже меняет список
+	last, err := s.repo.LastModified(ctx, actor.Scope(), workspaceID)
+	if err != nil {
+		return time.Time{}, Internal(err)
+	}
+	return last, nil
+}
+
 func (s *TaskService) GetByID(ctx context.Context, id uint) (*types.Task, error) { // получить задачу
-	task, err := s.repo.GetByID(ctx, id) // repo вызов
-	if err != nil {                      // маппим ошибки
+	actor, err := requireActor(ctx) // только свои задачи
+	if err != nil {
+		return nil, err
+	}
+	task, err := s.repo.GetByID(ctx, actor.Scope(), id) // repo вызов (чужая = not_found)
+	if err != nil {                                     // маппим ошибки
 		if errors.Is(err, repository.ErrNotFound) { // нет записи
 			return nil, NotFound(nil) // ошибка сервиса
 		}
@@ -73,45 +291,624 @@ func (s *TaskService) GetByID(ctx context.Context, id uint) (*types.Task, error)
 	return task, nil // ok
 }
 
-func (s *TaskService) Update(ctx context.Context, id uint, title *string, done *bool) (*types.Task, error) { // PATCH задачи
+type UpdateTaskOptions struct { // параметры PATCH помимо самих изменений
+	Force   bool  // закрыть задачу, даже если блокеры не выполнены
+	IfMatch *uint // If-Match: менять, только если версия задачи всё ещё эта
+}
+
+func (s *TaskService) Update(ctx context.Context, id uint, patch types.TaskPatch, opts UpdateTaskOptions) (*types.Task, error) { // PATCH задачи
+	return s.update(ctx, id, patch, opts, nil)
+}
+
+func (s *TaskService) update(ctx context.Context, id uint, patch types.TaskPatch, opts UpdateTaskOptions, revertedFrom *uint) (*types.Task, error) { // PATCH / откат (revertedFrom — ревизия)
 	if id == 0 { // id обязателен
 		return nil, Validation(map[string]string{"id": "required"})
 	}
-	if title == nil && done == nil { // нечего менять
-		return nil, Validation(map[string]string{
-			"title": "required",
-			"done":  "required",
-		})
+	if patch.Empty() { // нечего менять
+		return nil, Validation(map[string]string{"body": "nothing to update"})
 	}
 
-	if title != nil { // валидируем title
-		t := strings.TrimSpace(*title) // trim
-		if t == "" {                   // пусто нельзя
+	if patch.Title != nil { // валидируем title
+		t := strings.TrimSpace(*patch.Title) // trim
+		if t == "" {                         // пусто нельзя
 			return nil, Validation(map[string]string{"title": "required"})
 		}
-		title = &t // подменяем на очищенный
+		patch.Title = &t // подменяем на очищенный
+	}
+	if patch.Description != nil && len(*patch.Description) > maxDescriptionLen { // описание не безразмерное
+		return nil, Validation(map[string]string{"description": "too long"})
+	}
+	if patch.Priority != nil && !validPriority(*patch.Priority) { // известный приоритет
+		return nil, Validation(map[string]string{"priority": "must be low|medium|high|urgent"})
+	}
+	if patch.Estimate != nil && !validEstimate(*patch.Estimate) {
+		return nil, Validation(map[string]string{"estimate": "must be 0..1000"})
 	}
 
-	task, err := s.repo.Update(ctx, id, title, done) // обновление в repo
-	if err != nil {                                  // маппим ошибки
-		if errors.Is(err, repository.ErrNotFound) { // нет записи
-			return nil, NotFound(nil)
+	actor, current, err := s.authorizeWrite(ctx, id) // видна + есть права на запись
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion(current, opts.IfMatch); err != nil { // клиент правил устаревшую копию
+		return nil, err
+	}
+
+	if err := s.resolveStatus(ctx, current, &patch); err != nil { // статус/done по процессу
+		return nil, err
+	}
+	if patch.Done != nil && *patch.Done && !current.Done && !opts.Force { // закрываем — блокеры должны быть закрыты
+		blockers, err := s.repo.UnfinishedBlockers(ctx, id)
+		if err != nil {
+			return nil, Internal(err)
 		}
-		return nil, Internal(err) // прочее
+		if len(blockers) > 0 {
+			return nil, Conflict(map[string]any{"blocked_by": blockers, "hint": "finish blockers first or use ?force=true"})
+		}
+	}
+	if patch.ParentID != nil { // перенос в другого родителя — без циклов
+		if err := s.checkParent(ctx, actor, id, current.UserID, current.WorkspaceID, *patch.ParentID); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkRecurrencePatch(current, &patch); err != nil { // правило повторения и срок согласованы
+		return nil, err
+	}
+	setFields, clearFields, err := s.resolveFieldValues(ctx, current.WorkspaceID, patch.CustomFields) // типы значений по определениям полей
+	if err != nil {
+		return nil, err
+	}
+
+	patch.CompletedAt, patch.ClearCompletedAt = nil, false // completed_at ведёт только сервис
+	if patch.Done != nil && *patch.Done != current.Done {  // статус действительно меняется
+		if *patch.Done {
+			now := time.Now()
+			patch.CompletedAt = &now // выполнена сейчас
+		} else {
+			patch.ClearCompletedAt = true // вернули в работу
+		}
+	}
+
+	var task *types.Task
+	err = s.inTx(ctx, func(ctx context.Context) error { // поля, значения, ревизия и следующее вхождение — одной транзакцией
+		var err error
+		task, err = s.repo.Update(ctx, actor.Scope(), id, current.Version, patch) // условный UPDATE по прочитанной версии
+		if err != nil {                                                           // маппим ошибки
+			if errors.Is(err, repository.ErrNotFound) { // нет записи
+				return NotFound(nil)
+			}
+			if errors.Is(err, repository.ErrStale) { // кто-то успел записать между чтением и UPDATE
+				return staleVersion(opts.IfMatch)
+			}
+			return Internal(err) // прочее
+		}
+		if len(setFields) > 0 || len(clearFields) > 0 {
+			if err := s.fields.SetValues(ctx, id, setFields, clearFields); err != nil {
+				return Internal(err)
+			}
+			if task, err = s.repo.GetByID(ctx, actor.Scope(), id); err != nil { // перечитать значения с определениями
+				return Internal(err)
+			}
+		}
+		action := types.RevisionUpdate
+		if revertedFrom != nil {
+			action = types.RevisionRevert
+		}
+		if err := s.record(ctx, actor, action, current, task, revertedFrom); err != nil { // было -> стало
+			return err
+		}
+		if task.Title != current.Title || task.Status != current.Status || task.Done != current.Done || patch.ParentID != nil || patch.ClearParentID {
+			if err := s.touchRelated(ctx, current, task); err != nil { // прогресс родителя и ссылки зависимостей
+				return err
+			}
+		}
+		if task.Done { // закрыли вхождение серии — создаём следующее
+			next, err := s.spawnOccurrence(ctx, task)
+			if err != nil {
+				return err
+			}
+			if next != nil {
+				task.NextOccurrenceID = next
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	return task, nil // ok
 }
 
-func (s *TaskService) Delete(ctx context.Context, id uint) error { // удалить задачу
+func (s *TaskService) Delete(ctx context.Context, id uint, children string, ifMatch *uint) error { // удалить задачу в корзину (ifMatch — ожидаемая версия)
 	if id == 0 { // id обязателен
 		return Validation(map[string]string{"id": "required"})
 	}
-	err := s.repo.Delete(ctx, id) // удалить в repo
-	if err != nil {               // маппим ошибки
-		if errors.Is(err, repository.ErrNotFound) { // не найдено
-			return NotFound(nil)
+	if children == "" { // дефолт — не терять подзадачи молча
+		children = types.DeleteChildrenReject
+	}
+	if children != types.DeleteChildrenReject && children != types.DeleteChildrenOrphan && children != types.DeleteChildrenCascade {
+		return Validation(map[string]string{"children": "must be reject|orphan|cascade"})
+	}
+	actor, current, err := s.authorizeWrite(ctx, id) // видна + есть права на запись
+	if err != nil {
+		return err
+	}
+	if err := checkVersion(current, ifMatch); err != nil {
+		return err
+	}
+
+	return s.inTx(ctx, func(ctx context.Context) error { // удаление и ревизия — вместе
+		var (
+			err         error
+			descendants []types.Task  // уйдут в корзину вместе с задачей
+			orphans     []*types.Task // всплывут наверх (состояние до удаления)
+		)
+		switch children {
+		case types.DeleteChildrenReject: // есть подзадачи — отказ
+			n, cerr := s.repo.CountChildren(ctx, id)
+			if cerr != nil {
+				return Internal(cerr)
+			}
+			if n > 0 {
+				return Conflict(map[string]any{"children": n, "hint": "use ?children=orphan or ?children=cascade"})
+			}
+			err = s.repo.Delete(ctx, actor.Scope(), id, current.Version)
+		case types.DeleteChildrenOrphan: // подзадачи всплывают наверх
+			if orphans, err = s.childCards(ctx, id); err != nil {
+				return err
+			}
+			err = s.repo.Delete(ctx, actor.Scope(), id, current.Version)
+		case types.DeleteChildrenCascade: // вместе со всем поддеревом
+			if descendants, err = s.repo.Subtree(ctx, actor.Scope(), id); err != nil {
+				return Internal(err)
+			}
+			err = s.repo.DeleteSubtree(ctx, actor.Scope(), id, current.Version)
+		}
+		if err != nil { // маппим ошибки
+			if errors.Is(err, repository.ErrNotFound) { // не найдено
+				return NotFound(nil)
+			}
+			if errors.Is(err, repository.ErrStale) { // кто-то успел записать после проверки If-Match
+				return staleVersion(ifMatch)
+			}
+			return Internal(err) // прочее
+		}
+		for i := range descendants { // у каждой задачи поддерева — своя запись в истории
+			if err := s.record(ctx, actor, types.RevisionDelete, &descendants[i], nil, nil); err != nil {
+				return err
+			}
+		}
+		for _, before := range orphans { // parent_id подзадач сброшен — тоже в историю
+			after, err := s.repo.GetByID(ctx, repository.Scope{All: true}, before.ID)
+			if err != nil {
+				return Internal(err)
+			}
+			if err := s.record(ctx, actor, types.RevisionUpdate, before, after, nil); err != nil {
+				return err
+			}
+		}
+		if err := s.touchRelated(ctx, current); err != nil { // родитель и зависимости больше её не показывают
+			return err
+		}
+		return s.record(ctx, actor, types.RevisionDelete, current, nil, nil) // ok
+	})
+}
+
+func (s *TaskService) childCards(ctx context.Context, id uint) ([]*types.Task, error) { // прямые подзадачи целиком (как в карточке — для истории)
+	children, err := s.repo.Children(ctx, repository.Scope{All: true}, id) // repo.Delete отцепит все, не только видимые
+	if err != nil {
+		return nil, Internal(err)
+	}
+	cards := make([]*types.Task, 0, len(children))
+	for _, c := range children {
+		card, err := s.repo.GetByID(ctx, repository.Scope{All: true}, c.ID)
+		if err != nil {
+			return nil, Internal(err)
+		}
+		cards = append(cards, card)
+	}
+	return cards, nil
+}
+
+func checkVersion(task *types.Task, ifMatch *uint) error { // If-Match против текущей версии
+	if ifMatch != nil && *ifMatch != task.Version {
+		return PreconditionFailed(map[string]any{"version": task.Version, "hint": "task was modified, reload it and retry"})
+	}
+	return nil
+}
+
+func staleVersion(ifMatch *uint) error { // проиграли гонку на условном UPDATE
+	if ifMatch != nil { // клиент просил именно эту версию
+		return PreconditionFailed(map[string]string{"hint": "task was modified, reload it and retry"})
+	}
+	return Conflict(map[string]string{"hint": "task was modified concurrently, retry"})
+}
+
+func (s *TaskService) touched(ctx context.Context, id uint, others ...uint) (*types.Task, error) { // связи задачи поменялись: новая версия + свежая карточка
+	if err := s.repo.Touch(ctx, append([]uint{id}, others...)...); err != nil {
+		return nil, Internal(err)
+	}
+	return s.GetByID(ctx, id)
+}
+
+func (s *TaskService) touchRelated(ctx context.Context, tasks ...*types.Task) error { // задачи показаны в карточках родителя (прогресс) и зависимостей (ссылки) — сдвигаем и их Last-Modified
+	var ids []uint
+	for _, t := range tasks {
+		if t.ParentID != nil {
+			ids = append(ids, *t.ParentID)
+		}
+		for _, ref := range t.BlockedBy {
+			ids = append(ids, ref.ID)
+		}
+		for _, ref := range t.Blocking {
+			ids = append(ids, ref.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := s.repo.Touch(ctx, ids...); err != nil {
+		return Internal(err)
+	}
+	return nil
+}
+
+func (s *TaskService) changeRelations(ctx context.Context, actor Actor, before *types.Task, change func(ctx context.Context) error, others ...uint) (*types.Task, error) { // связи задачи: изменение, новая версия (и others) и ревизия — вместе
+	var task *types.Task
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		if err := change(ctx); err != nil {
+			return err
+		}
+		var err error
+		if task, err = s.touched(ctx, before.ID, others...); err != nil {
+			return err
 		}
-		return Internal(err) // прочее
+		return s.record(ctx, actor, types.RevisionUpdate, before, task, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+func (s *TaskService) Children(ctx context.Context, id uint) ([]types.Task, error) { // прямые подзадачи
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.GetByID(ctx, id); err != nil { // родитель должен быть виден
+		return nil, err
+	}
+	tasks, err := s.repo.Children(ctx, actor.Scope(), id)
+	if err != nil {
+		return nil, Internal(err)
+	}
+	return tasks, nil
+}
+
+func (s *TaskService) Subtree(ctx context.Context, id uint) (*types.Task, []types.Task, error) { // корень + все потомки
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	root, err := s.GetByID(ctx, id) // корень должен быть виден
+	if err != nil {
+		return nil, nil, err
+	}
+	tasks, err := s.repo.Subtree(ctx, actor.Scope(), id)
+	if err != nil {
+		return nil, nil, Internal(err)
+	}
+	return root, tasks, nil
+}
+
+func (s *TaskService) checkParent(ctx context.Context, actor Actor, id, userID uint, workspaceID *uint, parentID uint) error { // можно ли повесить задачу id под parentID
+	if parentID == id { // сам себе родитель
+		return Validation(map[string]string{"parent_id": "task cannot be its own parent"})
+	}
+	parent, err := s.repo.GetByID(ctx, actor.Scope(), parentID) // чужой родитель = не найден
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return Validation(map[string]string{"parent_id": "not found"})
+		}
+		return Internal(err)
+	}
+	if !sameHome(parent, userID, workspaceID) { // иерархия не пересекает пространства
+		return Validation(map[string]string{"parent_id": "must be in the same workspace"})
+	}
+	if id == 0 { // новая задача — циклу взяться неоткуда
+		return nil
+	}
+
+	ancestors, err := s.repo.AncestorIDs(ctx, parentID) // родитель и его предки
+	if err != nil {
+		return Internal(err)
+	}
+	if slices.Contains(ancestors, id) { // новый родитель — наш потомок
+		return Validation(map[string]string{"parent_id": "would create a cycle"})
+	}
+	return nil
+}
+
+func sameHome(t *types.Task, userID uint, workspaceID *uint) bool { // задача из того же пространства (или личная того же владельца)
+	if t.WorkspaceID == nil || workspaceID == nil {
+		return t.WorkspaceID == nil && workspaceID == nil && t.UserID == userID
+	}
+	return *t.WorkspaceID == *workspaceID
+}
+
+func (s *TaskService) AddDependency(ctx context.Context, id, blockerID uint) (*types.Task, error) { // id ждёт blockerID
+	if id == blockerID { // сам себя не блокирует
+		return nil, Validation(map[string]string{"blocked_by": "task cannot block itself"})
+	}
+	actor, task, err := s.authorizeWrite(ctx, id) // видна + есть права на запись
+	if err != nil {
+		return nil, err
+	}
+	blocker, err := s.repo.GetByID(ctx, actor.Scope(), blockerID) // чужой блокер = не найден
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, NotFound(map[string]string{"blocked_by": "not found"})
+		}
+		return nil, Internal(err)
+	}
+	if !sameHome(blocker, task.UserID, task.WorkspaceID) { // граф не пересекает пространства
+		return nil, Validation(map[string]string{"blocked_by": "must be in the same workspace"})
+	}
+
+	upstream, err := s.repo.BlockerIDs(ctx, blockerID) // от чего зависит блокер
+	if err != nil {
+		return nil, Internal(err)
+	}
+	if slices.Contains(upstream, id) { // блокер сам ждёт нас
+		return nil, Validation(map[string]string{"blocked_by": "would create a cycle"})
+	}
+
+	return s.changeRelations(ctx, actor, task, func(ctx context.Context) error {
+		if err := s.repo.AddDependency(ctx, id, blockerID); err != nil {
+			return Internal(err)
+		}
+		return nil
+	}, blockerID) // у блокера поменялся blocking
+}
+
+func (s *TaskService) RemoveDependency(ctx context.Context, id, blockerID uint) (*types.Task, error) { // снять зависимость
+	actor, task, err := s.authorizeWrite(ctx, id) // видна + есть права на запись
+	if err != nil {
+		return nil, err
+	}
+	return s.changeRelations(ctx, actor, task, func(ctx context.Context) error {
+		if err := s.repo.RemoveDependency(ctx, id, blockerID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) { // и не было
+				return NotFound(map[string]string{"blocked_by": "not a dependency"})
+			}
+			return Internal(err)
+		}
+		return nil
+	}, blockerID) // у блокера поменялся blocking
+}
+
+func (s *TaskService) checkAssignee(ctx context.Context, ownerID uint, workspaceID *uint, userID uint) error { // может ли userID исполнять задачу
+	if workspaceID == nil { // личную задачу делает только владелец
+		if userID != ownerID {
+			return Validation(map[string]string{"assignee": "personal tasks can only be assigned to their owner"})
+		}
+		return nil
+	}
+	m, err := s.workspaces.GetMember(ctx, *workspaceID, userID) // только участники пространства
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return Validation(map[string]string{"assignee": "not a workspace member"})
+		}
+		return Internal(err)
+	}
+	if types.WorkspaceRoleRank(m.Role) < types.WorkspaceRoleRank(types.WorkspaceRoleMember) { // viewer задачи не меняет
+		return Validation(map[string]string{"assignee": "viewers cannot be assigned"})
+	}
+	return nil
+}
+
+func (s *TaskService) Assign(ctx context.Context, id, userID uint) (*types.Task, error) { // назначить исполнителя
+	actor, task, err := s.authorizeWrite(ctx, id) // видна + есть права на запись
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkAssignee(ctx, task.UserID, task.WorkspaceID, userID); err != nil {
+		return nil, err
 	}
-	return nil // ok
+	return s.changeRelations(ctx, actor, task, func(ctx context.Context) error {
+		if err := s.repo.AddAssignee(ctx, id, userID); err != nil {
+			return Internal(err)
+		}
+		return nil
+	})
+}
+
+func (s *TaskService) Reassign(ctx context.Context, id, userID uint) (*types.Task, error) { // сделать userID единственным исполнителем
+	actor, task, err := s.authorizeWrite(ctx, id) // видна + есть права на запись
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkAssignee(ctx, task.UserID, task.WorkspaceID, userID); err != nil {
+		return nil, err
+	}
+	return s.changeRelations(ctx, actor, task, func(ctx context.Context) error {
+		for _, u := range task.Assignees { // снять остальных
+			if u.ID == userID {
+				continue
+			}
+			if err := s.repo.RemoveAssignee(ctx, id, u.ID); err != nil && !errors.Is(err, repository.ErrNotFound) { // сняли параллельно — не ошибка
+				return Internal(err)
+			}
+		}
+		if err := s.repo.AddAssignee(ctx, id, userID); err != nil {
+			return Internal(err)
+		}
+		return nil
+	})
+}
+
+func (s *TaskService) Unassign(ctx context.Context, id, userID uint) (*types.Task, error) { // снять исполнителя
+	actor, task, err := s.authorizeWrite(ctx, id) // видна + есть права на запись
+	if err != nil {
+		return nil, err
+	}
+	return s.changeRelations(ctx, actor, task, func(ctx context.Context) error {
+		if err := s.repo.RemoveAssignee(ctx, id, userID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) { // и не был назначен
+				return NotFound(map[string]string{"assignee": "not assigned"})
+			}
+			return Internal(err)
+		}
+		return nil
+	})
+}
+
+func (s *TaskService) Watch(ctx context.Context, id uint) (*types.Task, error) { // подписаться (достаточно видеть задачу)
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.GetByID(ctx, id) // чужая = not_found
+	if err != nil {
+		return nil, err
+	}
+	return s.changeRelations(ctx, actor, current, func(ctx context.Context) error {
+		if err := s.repo.AddWatcher(ctx, id, actor.UserID); err != nil {
+			return Internal(err)
+		}
+		return nil
+	})
+}
+
+func (s *TaskService) Unwatch(ctx context.Context, id uint) (*types.Task, error) { // отписаться
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.GetByID(ctx, id) // чужая = not_found
+	if err != nil {
+		return nil, err
+	}
+	return s.changeRelations(ctx, actor, current, func(ctx context.Context) error {
+		if err := s.repo.RemoveWatcher(ctx, id, actor.UserID); err != nil && !errors.Is(err, repository.ErrNotFound) { // не следил — не ошибка
+			return Internal(err)
+		}
+		return nil
+	})
+}
+
+func (s *TaskService) AddLabel(ctx context.Context, id, labelID uint) (*types.Task, error) { // повесить метку на задачу
+	actor, task, err := s.authorizeWrite(ctx, id) // видна + есть права на запись
+	if err != nil {
+		return nil, err
+	}
+	label, err := s.labels.GetByID(ctx, actor.Scope(), labelID) // чужая метка = not_found
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, NotFound(map[string]string{"label": "not found"})
+		}
+		return nil, Internal(err)
+	}
+	if !labelFits(task, label) { // метки пространства — только его задачам
+		return nil, Validation(map[string]string{"label": "belongs to another workspace or user"})
+	}
+	return s.changeRelations(ctx, actor, task, func(ctx context.Context) error {
+		if err := s.labels.Attach(ctx, task.ID, label.ID); err != nil {
+			return Internal(err)
+		}
+		return nil
+	})
+}
+
+func (s *TaskService) RemoveLabel(ctx context.Context, id, labelID uint) (*types.Task, error) { // снять метку с задачи
+	actor, task, err := s.authorizeWrite(ctx, id) // видна + есть права на запись
+	if err != nil {
+		return nil, err
+	}
+	return s.changeRelations(ctx, actor, task, func(ctx context.Context) error {
+		if err := s.labels.Detach(ctx, task.ID, labelID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) { // и не висела
+				return NotFound(map[string]string{"label": "not attached"})
+			}
+			return Internal(err)
+		}
+		return nil
+	})
+}
+
+func labelFits(task *types.Task, label *types.Label) bool { // из одного ли "владельца" задача и метка
+	if task.WorkspaceID == nil { // личная задача — личные метки её владельца
+		return label.WorkspaceID == nil && label.UserID == task.UserID
+	}
+	return label.WorkspaceID != nil && *label.WorkspaceID == *task.WorkspaceID
+}
+
+func (s *TaskService) authorizeWrite(ctx context.Context, id uint) (Actor, *types.Task, error) { // может ли вызывающий менять задачу
+	return authorizeTaskWrite(ctx, s.repo, s.workspaces, id)
+}
+
+func authorizeTaskWrite(ctx context.Context, tasks repository.TaskRepository, workspaces repository.WorkspaceRepository, id uint) (Actor, *types.Task, error) { // задача видна + роль member+ в её пространстве
+	actor, err := requireActor(ctx) // кто спрашивает
+	if err != nil {
+		return Actor{}, nil, err
+	}
+	task, err := tasks.GetByID(ctx, actor.Scope(), id) // чужая = not_found
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return Actor{}, nil, NotFound(nil)
+		}
+		return Actor{}, nil, Internal(err)
+	}
+	if task.WorkspaceID != nil { // viewer только читает
+		if err := requireWorkspaceRole(ctx, workspaces, actor, *task.WorkspaceID, types.WorkspaceRoleMember); err != nil {
+			return Actor{}, nil, err
+		}
+	}
+	return actor, task, nil // ok (task — текущее состояние)
+}
+
+func (s *TaskService) workflowFor(ctx context.Context, workspaceID *uint) (*types.Workflow, error) { // процесс задачи
+	if workspaceID == nil { // личные задачи — дефолтный процесс
+		return types.DefaultWorkflow(), nil
+	}
+	return loadWorkflow(ctx, s.workflows, *workspaceID)
+}
+
+func (s *TaskService) resolveStatus(ctx context.Context, current *types.Task, patch *types.TaskPatch) error { // Status/Done -> проверенный переход
+	if patch.Status == nil && patch.Done == nil { // статус не трогаем
+		return nil
+	}
+	wf, err := s.workflowFor(ctx, current.WorkspaceID)
+	if err != nil {
+		return err
+	}
+
+	target := current.Status // куда переходим
+	switch {
+	case patch.Status != nil: // явный статус
+		target = *patch.Status
+	case *patch.Done != current.Done && *patch.Done: // done=true -> "выполненный" статус
+		target = wf.DoneStatus()
+	case *patch.Done != current.Done: // done=false -> начальный статус
+		target = wf.Initial
+	}
+
+	st, ok := wf.Status(target)
+	if !ok {
+		return Validation(map[string]string{"status": "unknown status " + target})
+	}
+	if patch.Done != nil && *patch.Done != st.Done { // {"status": "done", "done": false}
+		return Validation(map[string]string{"done": "contradicts status"})
+	}
+	if !wf.CanTransition(current.Status, target) { // процесс запрещает
+		return InvalidTransition(map[string]any{
+			"from":    current.Status,
+			"to":      target,
+			"allowed": wf.NextStatuses(current.Status),
+		})
+	}
+
+	patch.Status = &st.Key // нормализованный статус
+	patch.Done = &st.Done  // done всегда производное
+	return nil
 }
@@ -0,0 +1,213 @@
+package service // сервисный слой
+
+import (
+	"context" // ctx
+	"errors"  // errors.Is
+	"strings" // TrimSpace
+	"time"    // started_at
+
+	"task-tracker/internal/domain/repository" // repo интерфейсы + ошибки
+	"task-tracker/internal/domain/types"      // модели
+)
+
+const ( // ограничения учёта времени
+	maxTimeEntryNote  = 2000                 // длина заметки
+	maxTimeEntry      = 24 * time.Hour       // одна ручная запись
+	maxTimeReportSpan = 366 * 24 * time.Hour // период отчёта
+)
+
+type TimeEntryService struct { // сервис учёта времени
+	repo       repository.TimeEntryRepository // записи
+	tasks      repository.TaskRepository      // видимость задачи
+	workspaces repository.WorkspaceRepository // роли в пространствах
+}
+
+func NewTimeEntryService(repo repository.TimeEntryRepository, tasks repository.TaskRepository, workspaces repository.WorkspaceRepository) *TimeEntryService { // конструктор
+	return &TimeEntryService{repo: repo, tasks: tasks, workspaces: workspaces} // сохранить зависимости
+}
+
+func normalizeTimeNote(note string) (string, error) { // trim + длина
+	note = strings.TrimSpace(note)
+	if len(note) > maxTimeEntryNote {
+		return "", Validation(map[string]string{"note": "too long"})
+	}
+	return note, nil
+}
+
+func (s *TimeEntryService) StartTimer(ctx context.Context, taskID uint, note string) (*types.TimeEntry, error) { // запустить таймер по задаче
+	note, err := normalizeTimeNote(note)
+	if err != nil {
+		return nil, err
+	}
+	actor, _, err := authorizeTaskWrite(ctx, s.tasks, s.workspaces, taskID) // видна + есть права на запись
+	if err != nil {
+		return nil, err
+	}
+
+	e := &types.TimeEntry{TaskID: taskID, UserID: actor.UserID, StartedAt: time.Now(), Note: note}
+	if err := s.repo.Create(ctx, e); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) { // таймер у пользователя уже идёт
+			details := map[string]any{"timer": "already running", "hint": "stop it first"}
+			if running, rerr := s.repo.Running(ctx, actor.UserID); rerr == nil {
+				details["task_id"] = running.TaskID
+			}
+			return nil, Conflict(details)
+		}
+		return nil, Internal(err)
+	}
+	return e, nil
+}
+
+func (s *TimeEntryService) StopTimer(ctx context.Context, taskID uint) (*types.TimeEntry, error) { // остановить свой таймер по задаче
+	// видимость задачи не проверяем: свой таймер можно остановить, даже если задачу удалили в корзину
+	// или пользователя убрали из пространства — иначе он навсегда займёт единственный слот idx_time_entries_running
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	running, err := s.repo.Running(ctx, actor.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, NotFound(map[string]string{"timer": "not running"})
+		}
+		return nil, Internal(err)
+	}
+	if running.TaskID != taskID { // идёт по другой задаче
+		return nil, Conflict(map[string]any{"timer": "running on another task", "task_id": running.TaskID})
+	}
+
+	e, err := s.repo.Stop(ctx, running.ID, time.Now())
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) { // параллельный stop успел раньше
+			return nil, NotFound(map[string]string{"timer": "not running"})
+		}
+		return nil, Internal(err)
+	}
+	if err := s.tasks.Touch(ctx, taskID); err != nil { // tracked_seconds в карточке задачи (и её Last-Modified)
+		return nil, Internal(err)
+	}
+	return e, nil
+}
+
+type ManualTimeEntryInput struct { // ручная запись времени
+	StartedAt *time.Time    // начало (nil = закончили только что)
+	Duration  time.Duration // длительность
+	Note      string        // что делали
+}
+
+func (s *TimeEntryService) AddEntry(ctx context.Context, taskID uint, in ManualTimeEntryInput) (*types.TimeEntry, error) { // записать время вручную
+	if in.Duration < time.Second || in.Duration > maxTimeEntry {
+		return nil, Validation(map[string]string{"duration_seconds": "must be 1..86400"})
+	}
+	note, err := normalizeTimeNote(in.Note)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	started := now.Add(-in.Duration) // дефолт — закончили сейчас
+	if in.StartedAt != nil {
+		started = *in.StartedAt
+	}
+	ended := started.Add(in.Duration)
+	if ended.After(now) { // будущее не учитываем
+		return nil, Validation(map[string]string{"started_at": "entry cannot end in the future"})
+	}
+	actor, _, err := authorizeTaskWrite(ctx, s.tasks, s.workspaces, taskID) // видна + есть права на запись
+	if err != nil {
+		return nil, err
+	}
+
+	e := &types.TimeEntry{
+		TaskID:    taskID,
+		UserID:    actor.UserID,
+		StartedAt: started,
+		EndedAt:   &ended,
+		Seconds:   int64(in.Duration / time.Second),
+		Note:      note,
+	}
+	if err := s.repo.Create(ctx, e); err != nil {
+		return nil, Internal(err)
+	}
+	if err := s.tasks.Touch(ctx, taskID); err != nil { // tracked_seconds в карточке задачи (и её Last-Modified)
+		return nil, Internal(err)
+	}
+	return e, nil
+}
+
+func (s *TimeEntryService) List(ctx context.Context, taskID uint) ([]types.TimeEntry, error) { // записи по задаче
+	if _, err := s.requireTask(ctx, taskID); err != nil {
+		return nil, err
+	}
+	list, err := s.repo.ListByTask(ctx, taskID)
+	if err != nil {
+		return nil, Internal(err)
+	}
+	return list, nil
+}
+
+func (s *TimeEntryService) Delete(ctx context.Context, taskID, id uint) error { // удалить запись (только автор)
+	actor, err := s.requireTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	e, err := s.repo.GetByID(ctx, taskID, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return NotFound(nil)
+		}
+		return Internal(err)
+	}
+	if e.UserID != actor.UserID { // чужое время не трогаем
+		return Forbidden(map[string]string{"time_entry": "only the author can delete it"})
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return NotFound(nil)
+		}
+		return Internal(err)
+	}
+	if err := s.tasks.Touch(ctx, taskID); err != nil { // tracked_seconds в карточке задачи (и её Last-Modified)
+		return Internal(err)
+	}
+	return nil
+}
+
+func (s *TimeEntryService) Report(ctx context.Context, filter types.TimeReportFilter) (*types.TimeReport, error) { // время по пользователям, меткам и дням
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if filter.To.IsZero() { // дефолт — по сегодняшний день включительно
+		filter.To = time.Now().UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+	}
+	if filter.From.IsZero() { // дефолт — последние 30 дней
+		filter.From = filter.To.Add(-30 * 24 * time.Hour)
+	}
+	if !filter.From.Before(filter.To) {
+		return nil, Validation(map[string]string{"from": "must be before to"})
+	}
+	if filter.To.Sub(filter.From) > maxTimeReportSpan {
+		return nil, Validation(map[string]string{"to": "range must not exceed 366 days"})
+	}
+
+	report, err := s.repo.Report(ctx, actor.Scope(), filter) // только видимые задачи
+	if err != nil {
+		return nil, Internal(err)
+	}
+	report.From, report.To = filter.From, filter.To
+	return report, nil
+}
+
+func (s *TimeEntryService) requireTask(ctx context.Context, taskID uint) (Actor, error) { // задача видна вызывающему
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return Actor{}, err
+	}
+	if _, err := s.tasks.GetByID(ctx, actor.Scope(), taskID); err != nil { // чужая = not_found
+		if errors.Is(err, repository.ErrNotFound) {
+			return Actor{}, NotFound(nil)
+		}
+		return Actor{}, Internal(err)
+	}
+	return actor, nil
+}
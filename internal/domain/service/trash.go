@@ -0,0 +1,104 @@
+package service // сервисный слой
+
+import (
+	"context" // ctx
+	"errors"  // errors.Is
+	"time"    // срок хранения
+
+	"task-tracker/internal/domain/repository" // repo интерфейсы + ошибки
+	"task-tracker/internal/domain/types"      // модели
+)
+
+const defaultTrashRetention = 30 * 24 * time.Hour // дефолтный срок хранения корзины
+
+func (s *TaskService) TrashRetention() time.Duration { return s.trashRetention } // когда удалённое сотрётся навсегда
+
+func (s *TaskService) Trash(ctx context.Context, workspaceID *uint, limit, offset int) ([]types.Task, error) { // видимые задачи в корзине
+	if limit <= 0 { // дефолт
+		limit = 20
+	}
+	if limit > 100 || offset < 0 {
+		return nil, Validation(map[string]string{
+			"limit":  "must be 1..100",
+			"offset": "must be >= 0",
+		})
+	}
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := s.repo.Trash(ctx, actor.Scope(), workspaceID, limit, offset)
+	if err != nil {
+		return nil, Internal(err)
+	}
+	return tasks, nil
+}
+
+func (s *TaskService) Restore(ctx context.Context, id uint) (*types.Task, error) { // вернуть задачу (и удалённое вместе с ней поддерево)
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	task, err := s.repo.GetDeleted(ctx, actor.Scope(), id) // чужая / не в корзине = not_found
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, NotFound(nil)
+		}
+		return nil, Internal(err)
+	}
+	if task.WorkspaceID != nil { // как и удалить — нужна роль member+
+		if err := requireWorkspaceRole(ctx, s.workspaces, actor, *task.WorkspaceID, types.WorkspaceRoleMember); err != nil {
+			return nil, err
+		}
+	}
+	if task.ParentID != nil { // родитель тоже должен быть на месте
+		if _, err := s.repo.GetByID(ctx, actor.Scope(), *task.ParentID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, Conflict(map[string]any{"parent_id": *task.ParentID, "hint": "restore the parent task first"})
+			}
+			return nil, Internal(err)
+		}
+	}
+
+	var restored *types.Task
+	err = s.inTx(ctx, func(ctx context.Context) error { // восстановление и ревизия — вместе
+		descendants, err := s.repo.Restore(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) { // параллельный restore / purge успел раньше
+				return NotFound(nil)
+			}
+			return Internal(err)
+		}
+		for _, childID := range descendants { // у каждой вернувшейся задачи — своя запись в истории
+			child, err := s.GetByID(ctx, childID)
+			if err != nil {
+				return err
+			}
+			if err := s.record(ctx, actor, types.RevisionRestore, child, child, nil); err != nil {
+				return err
+			}
+		}
+		if restored, err = s.GetByID(ctx, id); err != nil {
+			return err
+		}
+		if err := s.touchRelated(ctx, restored); err != nil { // родитель и зависимости снова её показывают
+			return err
+		}
+		return s.record(ctx, actor, types.RevisionRestore, task, restored, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
+
+func (s *TaskService) PurgeTrash(ctx context.Context) (int64, error) { // стереть задачи, пролежавшие в корзине дольше срока (фоновая задача)
+	n, keys, err := s.repo.Purge(ctx, time.Now().Add(-s.trashRetention))
+	if err != nil {
+		return 0, Internal(err)
+	}
+	if err := deleteBlobs(ctx, s.blobs, keys); err != nil { // строки уже удалены — остаётся только сообщить в лог
+		return n, Internal(err)
+	}
+	return n, nil
+}
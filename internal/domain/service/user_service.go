@@ -0,0 +1,185 @@
+package service // сервисный слой
+
+import (
+	"context"  // ctx
+	"errors"   // errors.Is
+	"net/mail" // парсинг email
+	"strings"  // TrimSpace/ToLower
+
+	"task-tracker/internal/domain/repository" // repo интерфейс + ошибки
+	"task-tracker/internal/domain/types"      // модели
+	"task-tracker/internal/storage"           // BlobStore
+)
+
+type UserService struct { // сервис пользователей
+	repo  repository.UserRepository // зависимость
+	blobs storage.BlobStore         // файлы его вложений при удалении
+}
+
+func NewUserService(repo repository.UserRepository, blobs storage.BlobStore) *UserService { // конструктор
+	return &UserService{repo: repo, blobs: blobs} // сохранить repo
+}
+
+func normalizeEmail(email string) (string, bool) { // trim + lower + проверка формата
+	email = strings.ToLower(strings.TrimSpace(email)) // чистим
+	addr, err := mail.ParseAddress(email)             // RFC 5322
+	if err != nil || addr.Address != email {          // мусор или "Имя <a@b>"
+		return "", false
+	}
+	return email, true // ok
+}
+
+func (s *UserService) Create(ctx context.Context, email string) (*types.User, error) { // создать пользователя
+	actor, err := requireActor(ctx) // заводить пользователей вручную может только админ
+	if err != nil {
+		return nil, err
+	}
+	if !actor.IsAdmin() {
+		return nil, Forbidden(nil)
+	}
+
+	email, ok := normalizeEmail(email) // валидируем email
+	if !ok {
+		return nil, Validation(map[string]string{"email": "invalid"})
+	}
+
+	user := &types.User{Email: email, Role: types.RoleUser} // собираем модель
+
+	if err := s.repo.Create(ctx, user); err != nil { // записываем в БД
+		if errors.Is(err, repository.ErrDuplicate) { // email занят
+			return nil, Conflict(map[string]string{"email": "already taken"})
+		}
+		return nil, Internal(err) // прочее
+	}
+	return user, nil // вернуть созданного
+}
+
+func (s *UserService) List(ctx context.Context, limit, offset int) ([]types.User, error) { // список пользователей
+	if limit <= 0 { // дефолт
+		limit = 20
+	}
+	if limit > 100 || offset < 0 {
+		return nil, Validation(map[string]string{
+			"limit":  "must be 1..100",
+			"offset": "must be >= 0",
+		})
+	}
+	actor, err := requireActor(ctx) // кто спрашивает
+	if err != nil {
+		return nil, err
+	}
+	if !actor.IsAdmin() { // обычный пользователь видит только себя
+		if offset > 0 {
+			return []types.User{}, nil
+		}
+		user, err := s.GetByID(ctx, actor.UserID)
+		if err != nil {
+			return nil, err
+		}
+		return []types.User{*user}, nil
+	}
+
+	users, err := s.repo.List(ctx, limit, offset)
+	if err != nil {
+		return nil, Internal(err)
+	}
+	return users, nil
+}
+
+func (s *UserService) GetByID(ctx context.Context, id uint) (*types.User, error) { // получить пользователя
+	if err := s.checkAccess(ctx, id); err != nil { // только себя / админ
+		return nil, err
+	}
+	user, err := s.repo.GetByID(ctx, id) // repo вызов
+	if err != nil {                      // маппим ошибки
+		if errors.Is(err, repository.ErrNotFound) { // нет записи
+			return nil, NotFound(nil)
+		}
+		return nil, Internal(err) // прочее
+	}
+	return user, nil // ok
+}
+
+func (s *UserService) Update(ctx context.Context, id uint, email, role *string) (*types.User, error) { // PATCH пользователя
+	if id == 0 { // id обязателен
+		return nil, Validation(map[string]string{"id": "required"})
+	}
+	if email == nil && role == nil { // нечего менять
+		return nil, Validation(map[string]string{
+			"email": "required",
+			"role":  "required",
+		})
+	}
+	if err := s.checkAccess(ctx, id); err != nil { // только себя / админ
+		return nil, err
+	}
+
+	if email != nil { // валидируем email
+		e, ok := normalizeEmail(*email)
+		if !ok {
+			return nil, Validation(map[string]string{"email": "invalid"})
+		}
+		email = &e // подменяем на очищенный
+	}
+	if role != nil { // роли раздаёт только админ
+		if actor, _ := ActorFrom(ctx); !actor.IsAdmin() {
+			return nil, Forbidden(map[string]string{"role": "only admin can change roles"})
+		}
+		if *role != types.RoleUser && *role != types.RoleAdmin {
+			return nil, Validation(map[string]string{"role": "must be user|admin"})
+		}
+	}
+
+	user, err := s.repo.Update(ctx, id, email, role) // обновление в repo
+	if err != nil {                                  // маппим ошибки
+		switch {
+		case errors.Is(err, repository.ErrNotFound): // нет записи
+			return nil, NotFound(nil)
+		case errors.Is(err, repository.ErrDuplicate): // email занят
+			return nil, Conflict(map[string]string{"email": "already taken"})
+		}
+		return nil, Internal(err) // прочее
+	}
+	return user, nil // ok
+}
+
+func (s *UserService) Delete(ctx context.Context, id uint) error { // удалить пользователя
+	if id == 0 { // id обязателен
+		return Validation(map[string]string{"id": "required"})
+	}
+	if err := s.checkAccess(ctx, id); err != nil { // только себя / админ
+		return err
+	}
+	keys, err := s.repo.Delete(ctx, id) // удалить в repo (вложения — каскадом)
+	if err != nil {                     // маппим ошибки
+		switch {
+		case errors.Is(err, repository.ErrNotFound): // не найдено
+			return NotFound(nil)
+		case errors.Is(err, repository.ErrReferenced): // остались задачи
+			return Conflict(map[string]string{"user": "has tasks"})
+		}
+		return Internal(err) // прочее
+	}
+	if err := deleteBlobs(ctx, s.blobs, keys); err != nil { // объекты удалённых вложений
+		return Internal(err)
+	}
+	return nil // ok
+}
+
+func (s *UserService) PromoteAdmins(ctx context.Context, emails []string) error { // выдать роль admin по списку (старт сервера)
+	if err := s.repo.SetRoleByEmails(ctx, emails, types.RoleAdmin); err != nil {
+		return Internal(err)
+	}
+	return nil
+}
+
+func (s *UserService) checkAccess(ctx context.Context, id uint) error { // чужой профиль — только админу
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return err
+	}
+	if id != actor.UserID && !actor.IsAdmin() { // не раскрываем существование
+		return NotFound(nil)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package service // сервисный слой
+
+import (
+	"context" // ctx
+	"errors"  // errors.Is
+	"regexp"  // формат ключа статуса
+	"strings" // TrimSpace
+
+	"task-tracker/internal/domain/repository" // repo интерфейсы + ошибки
+	"task-tracker/internal/domain/types"      // модели
+)
+
+var statusKeyRe = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`) // ключ статуса: snake_case до 32 символов
+
+func loadWorkflow(ctx context.Context, repo repository.WorkflowRepository, workspaceID uint) (*types.Workflow, error) { // процесс пространства
+	wf, err := repo.Get(ctx, workspaceID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) { // не настраивали — дефолтный
+			wf = types.DefaultWorkflow()
+			wf.WorkspaceID = workspaceID
+			return wf, nil
+		}
+		return nil, Internal(err)
+	}
+	return wf, nil
+}
+
+func validateWorkflow(wf *types.Workflow) error { // структурная проверка процесса
+	if len(wf.Statuses) == 0 || len(wf.Statuses) > 20 {
+		return Validation(map[string]string{"statuses": "must be 1..20"})
+	}
+
+	seen := make(map[string]bool, len(wf.Statuses)) // уникальность ключей
+	hasDone := false
+	for i := range wf.Statuses {
+		st := &wf.Statuses[i]
+		st.Name = strings.TrimSpace(st.Name)
+		if !statusKeyRe.MatchString(st.Key) {
+			return Validation(map[string]string{"statuses": "invalid key " + st.Key})
+		}
+		if seen[st.Key] {
+			return Validation(map[string]string{"statuses": "duplicate key " + st.Key})
+		}
+		if st.Name == "" { // имя по умолчанию = ключ
+			st.Name = st.Key
+		}
+		seen[st.Key] = true
+		hasDone = hasDone || st.Done
+	}
+	if !hasDone { // иначе done=true некуда переводить
+		return Validation(map[string]string{"statuses": "at least one done status required"})
+	}
+	if !seen[wf.Initial] {
+		return Validation(map[string]string{"initial": "must be one of statuses"})
+	}
+
+	for _, t := range wf.Transitions { // переходы только между известными статусами
+		if !seen[t.From] || !seen[t.To] || t.From == t.To {
+			return Validation(map[string]string{"transitions": "invalid transition " + t.From + " -> " + t.To})
+		}
+	}
+	return nil
+}
+
+func (s *WorkspaceService) Workflow(ctx context.Context, id uint) (*types.Workflow, error) { // процесс (любой участник)
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireWorkspaceRole(ctx, s.repo, actor, id, types.WorkspaceRoleViewer); err != nil {
+		return nil, err
+	}
+	return loadWorkflow(ctx, s.workflows, id)
+}
+
+func (s *WorkspaceService) SetWorkflow(ctx context.Context, id uint, wf *types.Workflow) (*types.Workflow, error) { // заменить процесс (admin+)
+	if err := validateWorkflow(wf); err != nil {
+		return nil, err
+	}
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireWorkspaceRole(ctx, s.repo, actor, id, types.WorkspaceRoleAdmin); err != nil {
+		return nil, err
+	}
+
+	used, err := s.workflows.UsedStatuses(ctx, id) // задачи не должны остаться в удалённом статусе
+	if err != nil {
+		return nil, Internal(err)
+	}
+	for _, st := range used {
+		if _, ok := wf.Status(st); !ok {
+			return nil, Conflict(map[string]string{"statuses": "tasks still use status " + st})
+		}
+	}
+
+	wf.WorkspaceID = id
+	if err := s.workflows.Save(ctx, wf); err != nil {
+		return nil, Internal(err)
+	}
+	return wf, nil
+}
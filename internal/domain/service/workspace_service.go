@@ -0,0 +1,401 @@
+package service // сервисный слой
+
+import (
+	"context"         // ctx
+	"crypto/rand"     // токены приглашений
+	"encoding/base64" // кодирование токена
+	"errors"          // errors.Is
+	"strings"         // TrimSpace/EqualFold
+	"time"            // TTL приглашения
+
+	"task-tracker/internal/domain/repository" // repo интерфейсы + ошибки
+	"task-tracker/internal/domain/types"      // модели
+	"task-tracker/internal/storage"           // BlobStore
+)
+
+type WorkspaceService struct { // сервис рабочих пространств
+	repo      repository.WorkspaceRepository // пространства/участники/приглашения
+	users     repository.UserRepository      // email принимающего
+	workflows repository.WorkflowRepository  // процессы статусов
+	blobs     storage.BlobStore              // файлы вложений удаляемых задач
+	inviteTTL time.Duration                  // срок жизни приглашения
+}
+
+func NewWorkspaceService(repo repository.WorkspaceRepository, users repository.UserRepository, workflows repository.WorkflowRepository, blobs storage.BlobStore, inviteTTL time.Duration) *WorkspaceService { // конструктор
+	return &WorkspaceService{repo: repo, users: users, workflows: workflows, blobs: blobs, inviteTTL: inviteTTL} // сохранить зависимости
+}
+
+func requireWorkspaceRole(ctx context.Context, repo repository.WorkspaceRepository, actor Actor, workspaceID uint, minRole string) error { // роль не ниже minRole
+	if actor.IsAdmin() { // админ обходит роли, но пространство должно существовать
+		if _, err := repo.GetByID(ctx, workspaceID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return NotFound(map[string]string{"workspace": "not found"})
+			}
+			return Internal(err)
+		}
+		return nil
+	}
+
+	m, err := repo.GetMember(ctx, workspaceID, actor.UserID) // членство
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) { // не участник — не раскрываем существование
+			return NotFound(map[string]string{"workspace": "not found"})
+		}
+		return Internal(err)
+	}
+	if types.WorkspaceRoleRank(m.Role) < types.WorkspaceRoleRank(minRole) { // роли не хватает
+		return Forbidden(map[string]string{"role": minRole + " required"})
+	}
+	return nil // ok
+}
+
+func validWorkspaceRole(role string) bool { return types.WorkspaceRoleRank(role) > 0 } // известная роль?
+
+func (s *WorkspaceService) Create(ctx context.Context, name string) (*types.Workspace, error) { // создать пространство
+	actor, err := requireActor(ctx) // создатель становится owner
+	if err != nil {
+		return nil, err
+	}
+	name = strings.TrimSpace(name) // чистим name
+	if name == "" || len(name) > 100 {
+		return nil, Validation(map[string]string{"name": "must be 1..100 chars"})
+	}
+
+	ws := &types.Workspace{Name: name}                           // собираем модель
+	if err := s.repo.Create(ctx, ws, actor.UserID); err != nil { // пространство + owner
+		return nil, Internal(err)
+	}
+	return ws, nil // ok
+}
+
+func (s *WorkspaceService) List(ctx context.Context) ([]types.Workspace, error) { // пространства вызывающего
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	list, err := s.repo.List(ctx, actor.Scope())
+	if err != nil {
+		return nil, Internal(err)
+	}
+	return list, nil
+}
+
+func (s *WorkspaceService) GetByID(ctx context.Context, id uint) (*types.Workspace, error) { // получить (любой участник)
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireWorkspaceRole(ctx, s.repo, actor, id, types.WorkspaceRoleViewer); err != nil {
+		return nil, err
+	}
+	ws, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, NotFound(nil)
+		}
+		return nil, Internal(err)
+	}
+	return ws, nil
+}
+
+const maxWeeklyCapacity = 1000 // ёмкость в неделю (часы или points)
+
+func validCapacity(c float64) bool { return c >= 0 && c <= maxWeeklyCapacity } // разумная ёмкость?
+
+func (s *WorkspaceService) Update(ctx context.Context, id uint, patch types.WorkspacePatch) (*types.Workspace, error) { // название и настройки (admin+)
+	if patch.Empty() { // нечего менять
+		return nil, Validation(map[string]string{"body": "nothing to update"})
+	}
+	if patch.Name != nil {
+		name := strings.TrimSpace(*patch.Name) // чистим name
+		if name == "" || len(name) > 100 {
+			return nil, Validation(map[string]string{"name": "must be 1..100 chars"})
+		}
+		patch.Name = &name
+	}
+	if patch.EstimateUnit != nil && *patch.EstimateUnit != types.EstimateUnitPoints && *patch.EstimateUnit != types.EstimateUnitHours {
+		return nil, Validation(map[string]string{"estimate_unit": "must be points|hours"})
+	}
+	if patch.WeeklyCapacity != nil && !validCapacity(*patch.WeeklyCapacity) {
+		return nil, Validation(map[string]string{"weekly_capacity": "must be 0..1000"})
+	}
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireWorkspaceRole(ctx, s.repo, actor, id, types.WorkspaceRoleAdmin); err != nil {
+		return nil, err
+	}
+	ws, err := s.repo.Update(ctx, id, patch)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, NotFound(nil)
+		}
+		return nil, Internal(err)
+	}
+	return ws, nil
+}
+
+func (s *WorkspaceService) Delete(ctx context.Context, id uint) error { // удалить (только owner)
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return err
+	}
+	if err := requireWorkspaceRole(ctx, s.repo, actor, id, types.WorkspaceRoleOwner); err != nil {
+		return err
+	}
+	keys, err := s.repo.Delete(ctx, id) // каскадно с задачами и вложениями
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return NotFound(nil)
+		}
+		return Internal(err)
+	}
+	if err := deleteBlobs(ctx, s.blobs, keys); err != nil { // как AttachmentService.Delete: строки удалены, объект — нет
+		return Internal(err)
+	}
+	return nil
+}
+
+func (s *WorkspaceService) Members(ctx context.Context, id uint) ([]types.WorkspaceMember, error) { // участники (любой участник)
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireWorkspaceRole(ctx, s.repo, actor, id, types.WorkspaceRoleViewer); err != nil {
+		return nil, err
+	}
+	members, err := s.repo.ListMembers(ctx, id)
+	if err != nil {
+		return nil, Internal(err)
+	}
+	return members, nil
+}
+
+func (s *WorkspaceService) UpdateMemberRole(ctx context.Context, id, userID uint, role string) error { // сменить роль (admin+)
+	if !validWorkspaceRole(role) {
+		return Validation(map[string]string{"role": "must be owner|admin|member|viewer"})
+	}
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return err
+	}
+	if err := requireWorkspaceRole(ctx, s.repo, actor, id, types.WorkspaceRoleAdmin); err != nil {
+		return err
+	}
+
+	target, err := s.repo.GetMember(ctx, id, userID) // кого меняем
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return NotFound(map[string]string{"member": "not found"})
+		}
+		return Internal(err)
+	}
+	if target.Role == types.WorkspaceRoleOwner || role == types.WorkspaceRoleOwner { // владельцев назначает/снимает только owner
+		if err := requireWorkspaceRole(ctx, s.repo, actor, id, types.WorkspaceRoleOwner); err != nil {
+			return err
+		}
+	}
+	if target.Role == types.WorkspaceRoleOwner && role != types.WorkspaceRoleOwner { // последнего владельца не понижаем
+		if err := s.ensureAnotherOwner(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	if err := s.repo.UpdateMemberRole(ctx, id, userID, role); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return NotFound(map[string]string{"member": "not found"})
+		}
+		return Internal(err)
+	}
+	return nil
+}
+
+func (s *WorkspaceService) SetMemberCapacity(ctx context.Context, id, userID uint, capacity *float64) error { // своя ёмкость участника (admin+)
+	if capacity != nil && !validCapacity(*capacity) {
+		return Validation(map[string]string{"weekly_capacity": "must be 0..1000"})
+	}
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return err
+	}
+	if err := requireWorkspaceRole(ctx, s.repo, actor, id, types.WorkspaceRoleAdmin); err != nil {
+		return err
+	}
+	if err := s.repo.UpdateMemberCapacity(ctx, id, userID, capacity); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return NotFound(map[string]string{"member": "not found"})
+		}
+		return Internal(err)
+	}
+	return nil
+}
+
+func (s *WorkspaceService) RemoveMember(ctx context.Context, id, userID uint) error { // исключить (admin+) или выйти самому
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return err
+	}
+	minRole := types.WorkspaceRoleAdmin // чужого исключает admin+
+	if userID == actor.UserID {         // выйти может любой участник
+		minRole = types.WorkspaceRoleViewer
+	}
+	if err := requireWorkspaceRole(ctx, s.repo, actor, id, minRole); err != nil {
+		return err
+	}
+
+	target, err := s.repo.GetMember(ctx, id, userID) // кого исключаем
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return NotFound(map[string]string{"member": "not found"})
+		}
+		return Internal(err)
+	}
+	if target.Role == types.WorkspaceRoleOwner { // владельца убирает только owner и не последнего
+		if userID != actor.UserID {
+			if err := requireWorkspaceRole(ctx, s.repo, actor, id, types.WorkspaceRoleOwner); err != nil {
+				return err
+			}
+		}
+		if err := s.ensureAnotherOwner(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	if err := s.repo.RemoveMember(ctx, id, userID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return NotFound(map[string]string{"member": "not found"})
+		}
+		return Internal(err)
+	}
+	return nil
+}
+
+func (s *WorkspaceService) Invite(ctx context.Context, id uint, email, role string) (*types.WorkspaceInvitation, string, error) { // пригласить по email (admin+)
+	email, ok := normalizeEmail(email) // валидируем email
+	if !ok {
+		return nil, "", Validation(map[string]string{"email": "invalid"})
+	}
+	if role == "" { // по умолчанию обычный участник
+		role = types.WorkspaceRoleMember
+	}
+	if !validWorkspaceRole(role) {
+		return nil, "", Validation(map[string]string{"role": "must be owner|admin|member|viewer"})
+	}
+
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	minRole := types.WorkspaceRoleAdmin   // приглашает admin+
+	if role == types.WorkspaceRoleOwner { // владельцем может пригласить только owner
+		minRole = types.WorkspaceRoleOwner
+	}
+	if err := requireWorkspaceRole(ctx, s.repo, actor, id, minRole); err != nil {
+		return nil, "", err
+	}
+
+	raw := make([]byte, 32)                   // 256 бит энтропии
+	if _, err := rand.Read(raw); err != nil { // crypto/rand
+		return nil, "", Internal(err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw) // отдаём пригласившему один раз
+
+	inv := &types.WorkspaceInvitation{ // в БД только хеш
+		WorkspaceID: id,
+		Email:       email,
+		Role:        role,
+		TokenHash:   hashToken(token),
+		InvitedByID: actor.UserID,
+		ExpiresAt:   time.Now().Add(s.inviteTTL),
+	}
+	if err := s.repo.CreateInvitation(ctx, inv); err != nil {
+		return nil, "", Internal(err)
+	}
+	return inv, token, nil // ok
+}
+
+func (s *WorkspaceService) Invitations(ctx context.Context, id uint) ([]types.WorkspaceInvitation, error) { // ожидающие приглашения (admin+)
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireWorkspaceRole(ctx, s.repo, actor, id, types.WorkspaceRoleAdmin); err != nil {
+		return nil, err
+	}
+	list, err := s.repo.ListInvitations(ctx, id)
+	if err != nil {
+		return nil, Internal(err)
+	}
+	return list, nil
+}
+
+func (s *WorkspaceService) RevokeInvitation(ctx context.Context, id, invitationID uint) error { // отозвать приглашение (admin+)
+	actor, err := requireActor(ctx)
+	if err != nil {
+		return err
+	}
+	if err := requireWorkspaceRole(ctx, s.repo, actor, id, types.WorkspaceRoleAdmin); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteInvitation(ctx, id, invitationID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return NotFound(nil)
+		}
+		return Internal(err)
+	}
+	return nil
+}
+
+func (s *WorkspaceService) AcceptInvitation(ctx context.Context, token string) (*types.Workspace, error) { // принять приглашение
+	actor, err := requireActor(ctx) // принимает текущий пользователь
+	if err != nil {
+		return nil, err
+	}
+
+	inv, err := s.repo.GetInvitationByHash(ctx, hashToken(token)) // ищем по хешу
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, NotFound(map[string]string{"invitation": "not found"})
+		}
+		return nil, Internal(err)
+	}
+	if inv.AcceptedAt != nil || time.Now().After(inv.ExpiresAt) { // использовано / истекло
+		return nil, NotFound(map[string]string{"invitation": "expired"})
+	}
+
+	user, err := s.users.GetByID(ctx, actor.UserID) // приглашение адресное
+	if err != nil {
+		return nil, Internal(err)
+	}
+	if !strings.EqualFold(user.Email, inv.Email) { // чужое приглашение — как будто его нет
+		return nil, NotFound(map[string]string{"invitation": "not found"})
+	}
+
+	if err := s.repo.AcceptInvitation(ctx, inv, actor.UserID); err != nil { // участник + отметка
+		switch {
+		case errors.Is(err, repository.ErrNotFound): // принято параллельно
+			return nil, NotFound(map[string]string{"invitation": "expired"})
+		case errors.Is(err, repository.ErrDuplicate): // уже участник
+			return nil, Conflict(map[string]string{"member": "already in workspace"})
+		}
+		return nil, Internal(err)
+	}
+
+	ws, err := s.repo.GetByID(ctx, inv.WorkspaceID) // куда вступили
+	if err != nil {
+		return nil, Internal(err)
+	}
+	return ws, nil // ok
+}
+
+func (s *WorkspaceService) ensureAnotherOwner(ctx context.Context, id uint) error { // в пространстве должен остаться владелец
+	n, err := s.repo.CountByRole(ctx, id, types.WorkspaceRoleOwner)
+	if err != nil {
+		return Internal(err)
+	}
+	if n <= 1 {
+		return Conflict(map[string]string{"owner": "workspace must keep at least one owner"})
+	}
+	return nil
+}
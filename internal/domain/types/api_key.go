@@ -0,0 +1,37 @@
+package types // пакет с моделями/типами
+
+import (
+	"strings" // разбор scopes
+	"time"    // time.Time
+)
+
+const ( // права API-ключей
+	ScopeTasksRead       = "tasks:read"       // чтение задач
+	ScopeTasksWrite      = "tasks:write"      // создание/изменение/удаление задач
+	ScopeUsersRead       = "users:read"       // чтение профилей
+	ScopeUsersWrite      = "users:write"      // изменение профилей
+	ScopeWorkspacesRead  = "workspaces:read"  // чтение пространств и участников
+	ScopeWorkspacesWrite = "workspaces:write" // управление пространствами, участниками, приглашениями
+)
+
+var APIKeyScopes = []string{ // допустимые scopes
+	ScopeTasksRead, ScopeTasksWrite,
+	ScopeUsersRead, ScopeUsersWrite,
+	ScopeWorkspacesRead, ScopeWorkspacesWrite,
+}
+
+type APIKey struct { // персональный API-ключ (GORM)
+	ID         uint       `gorm:"primaryKey"`           // PK
+	UserID     uint       `gorm:"index;not null"`       // владелец
+	Name       string     `gorm:"not null"`             // название ("ci", "cron backup")
+	Prefix     string     `gorm:"not null"`             // начало ключа для опознания в списке
+	KeyHash    string     `gorm:"uniqueIndex;not null"` // sha256 от ключа (сам ключ не храним)
+	Scopes     string     `gorm:"not null;default:''"`  // scopes через пробел
+	LastUsedAt *time.Time // последнее использование
+	RevokedAt  *time.Time // когда отозван (nil = активен)
+	CreatedAt  time.Time  // автозаполняется GORM
+
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"` // FK: удаление пользователя гасит ключи
+}
+
+func (k *APIKey) ScopeList() []string { return strings.Fields(k.Scopes) } // scopes списком
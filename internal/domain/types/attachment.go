@@ -0,0 +1,18 @@
+package types // пакет с моделями/типами
+
+import "time" // time.Time
+
+type Attachment struct { // метаданные вложения (байты — в BlobStore)
+	ID          uint      `gorm:"primaryKey"`           // PK
+	TaskID      uint      `gorm:"not null;index"`       // задача
+	UploaderID  uint      `gorm:"not null;index"`       // кто загрузил
+	Filename    string    `gorm:"not null"`             // имя файла (очищенное)
+	ContentType string    `gorm:"not null"`             // определён по содержимому
+	Size        int64     `gorm:"not null"`             // байты
+	SHA256      string    `gorm:"not null;size:64"`     // hex контрольная сумма
+	StorageKey  string    `gorm:"not null;uniqueIndex"` // ключ в BlobStore
+	CreatedAt   time.Time // автозаполняется GORM
+
+	Task     *Task `gorm:"foreignKey:TaskID;constraint:OnDelete:CASCADE"`     // FK: строки уходят с задачей (объекты удаляет сервис по ключам, собранным до DELETE)
+	Uploader *User `gorm:"foreignKey:UploaderID;constraint:OnDelete:CASCADE"` // FK: автор
+}
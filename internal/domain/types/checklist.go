@@ -0,0 +1,14 @@
+package types // пакет с моделями/типами
+
+import "time" // time.Time
+
+type ChecklistItem struct { // пункт чек-листа задачи
+	ID        uint       `gorm:"primaryKey"`                                       // PK
+	TaskID    uint       `gorm:"not null;index:idx_checklist_task_pos,priority:1"` // задача
+	Position  int        `gorm:"not null;index:idx_checklist_task_pos,priority:2"` // порядок (0..n-1)
+	Title     string     `gorm:"not null"`                                         // текст пункта
+	Done      bool       `gorm:"not null;default:false"`                           // отмечен
+	DoneAt    *time.Time // когда отмечен
+	CreatedAt time.Time  // автозаполняется GORM
+	UpdatedAt time.Time  // автозаполняется GORM
+}
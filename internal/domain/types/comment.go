@@ -0,0 +1,19 @@
+package types // пакет с моделями/типами
+
+import "time" // time.Time
+
+type Comment struct { // комментарий к задаче
+	ID        uint       `gorm:"primaryKey"`         // PK
+	TaskID    uint       `gorm:"not null;index"`     // задача
+	AuthorID  uint       `gorm:"not null;index"`     // автор
+	ParentID  *uint      `gorm:"index"`              // ответ на комментарий (nil = верхний уровень)
+	Body      string     `gorm:"type:text;not null"` // текст (markdown)
+	EditedAt  *time.Time // когда правили (nil = не правили)
+	DeletedAt *time.Time // удалён, но на него есть ответы — остаётся заглушкой
+	CreatedAt time.Time  // автозаполняется GORM
+	UpdatedAt time.Time  // автозаполняется GORM
+
+	Task   *Task    `gorm:"foreignKey:TaskID;constraint:OnDelete:CASCADE"`   // FK: комментарии уходят вместе с задачей
+	Author *User    `gorm:"foreignKey:AuthorID;constraint:OnDelete:CASCADE"` // FK: автор
+	Parent *Comment `gorm:"foreignKey:ParentID;constraint:OnDelete:CASCADE"` // FK: ветка уходит вместе с корнем
+}
@@ -0,0 +1,83 @@
+package types // пакет с моделями/типами
+
+import "time" // time.Time
+
+const ( // типы пользовательских полей
+	FieldTypeText        = "text"         // строка
+	FieldTypeNumber      = "number"       // число
+	FieldTypeDate        = "date"         // дата без времени
+	FieldTypeSelect      = "select"       // один вариант из Options
+	FieldTypeMultiSelect = "multi_select" // несколько вариантов из Options
+	FieldTypeUser        = "user"         // участник пространства
+)
+
+var FieldTypes = []string{FieldTypeText, FieldTypeNumber, FieldTypeDate, FieldTypeSelect, FieldTypeMultiSelect, FieldTypeUser} // допустимые типы
+
+type CustomField struct { // пользовательское поле задач пространства
+	ID          uint      `gorm:"primaryKey"`                                               // PK
+	WorkspaceID uint      `gorm:"not null;uniqueIndex:idx_custom_fields_ws_key,priority:1"` // пространство
+	Key         string    `gorm:"not null;uniqueIndex:idx_custom_fields_ws_key,priority:2"` // ключ в API (customer, env, ...)
+	Name        string    `gorm:"not null"`                                                 // подпись для людей
+	Type        string    `gorm:"not null"`                                                 // FieldTypes (не меняется после создания)
+	Options     []string  `gorm:"type:jsonb;serializer:json;not null;default:'[]'"`         // варианты select / multi_select
+	CreatedAt   time.Time // автозаполняется GORM
+	UpdatedAt   time.Time // автозаполняется GORM
+
+	Workspace *Workspace `gorm:"foreignKey:WorkspaceID;constraint:OnDelete:CASCADE"` // FK: поля уходят вместе с пространством
+}
+
+func (f *CustomField) HasOptions() bool { // select / multi_select?
+	return f.Type == FieldTypeSelect || f.Type == FieldTypeMultiSelect
+}
+
+type TaskFieldValue struct { // значение пользовательского поля у задачи (заполнена одна колонка по типу поля)
+	TaskID      uint       `gorm:"primaryKey"`       // PK + FK на задачу
+	FieldID     uint       `gorm:"primaryKey;index"` // PK + FK на поле
+	TextValue   *string    `gorm:"type:text"`        // text / select
+	NumberValue *float64   // number
+	DateValue   *time.Time `gorm:"type:date"`                  // date
+	UserValue   *uint      `gorm:"index"`                      // user
+	MultiValue  []string   `gorm:"type:jsonb;serializer:json"` // multi_select
+	UpdatedAt   time.Time  // автозаполняется GORM
+
+	Task  *Task        `gorm:"foreignKey:TaskID;constraint:OnDelete:CASCADE"`    // FK: значения уходят вместе с задачей
+	Field *CustomField `gorm:"foreignKey:FieldID;constraint:OnDelete:CASCADE"`   // FK: и вместе с полем
+	User  *User        `gorm:"foreignKey:UserValue;constraint:OnDelete:CASCADE"` // FK: пользователь удалён — значения нет
+}
+
+func (v *TaskFieldValue) Value(fieldType string) any { // значение для API (string / float64 / "YYYY-MM-DD" / uint / []string)
+	switch fieldType {
+	case FieldTypeText, FieldTypeSelect:
+		if v.TextValue != nil {
+			return *v.TextValue
+		}
+	case FieldTypeNumber:
+		if v.NumberValue != nil {
+			return *v.NumberValue
+		}
+	case FieldTypeDate:
+		if v.DateValue != nil {
+			return v.DateValue.Format(time.DateOnly)
+		}
+	case FieldTypeUser:
+		if v.UserValue != nil {
+			return *v.UserValue
+		}
+	case FieldTypeMultiSelect:
+		if v.MultiValue != nil {
+			return v.MultiValue
+		}
+	}
+	return nil
+}
+
+type FieldCond struct { // условие ?cf[key]=value (разбирает сервис)
+	Field CustomField // поле (ID + Type)
+	Value any         // значение в типе поля (string / float64 / time.Time / uint)
+}
+
+type TaskSort struct { // порядок списка задач (разбирает сервис)
+	Column string       // встроенная колонка tasks ("" = пользовательское поле)
+	Field  *CustomField // пользовательское поле
+	Desc   bool         // по убыванию
+}
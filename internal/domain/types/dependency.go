@@ -0,0 +1,19 @@
+package types // пакет с моделями/типами
+
+import "time" // time.Time
+
+type TaskDependency struct { // ребро графа: TaskID не начать, пока не закрыта BlockedByID
+	TaskID      uint      `gorm:"primaryKey"`       // заблокированная задача
+	BlockedByID uint      `gorm:"primaryKey;index"` // блокирующая задача
+	CreatedAt   time.Time // автозаполняется GORM
+
+	Task      *Task `gorm:"foreignKey:TaskID;constraint:OnDelete:CASCADE"`      // FK: ребро уходит вместе с задачей
+	BlockedBy *Task `gorm:"foreignKey:BlockedByID;constraint:OnDelete:CASCADE"` // FK: и вместе с блокирующей
+}
+
+type TaskRef struct { // краткая ссылка на задачу (для blocked_by/blocking)
+	ID     uint   // id
+	Title  string // заголовок
+	Status string // статус
+	Done   bool   // выполнена
+}
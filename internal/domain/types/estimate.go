@@ -0,0 +1,36 @@
+package types // пакет с моделями/типами
+
+import "time" // time.Time
+
+type EstimateSum struct { // сумма оценок в одной единице
+	Unit      string  // points / hours
+	Estimated int64   // задач с оценкой
+	Total     float64 // сумма оценок
+	Remaining float64 // сумма оценок невыполненных
+}
+
+type TaskAggregate struct { // агрегаты по всей выборке (без limit/offset)
+	Count     int64         // задач в выборке
+	Estimates []EstimateSum // по единицам (личные задачи — в points)
+}
+
+type AssignedEstimate struct { // нагрузка исполнителя
+	UserID   uint    // исполнитель
+	Tasks    int64   // открытых задач
+	Estimate float64 // сумма их оценок
+}
+
+type CapacityRow struct { // участник в отчёте о ёмкости
+	UserID   uint    // участник
+	Email    string  // email
+	Capacity float64 // ёмкость в неделю (0 = не задана)
+	Tasks    int64   // открытых назначенных задач
+	Assigned float64 // сумма их оценок
+}
+
+type CapacityReport struct { // ёмкость против назначенных оценок
+	WorkspaceID uint          // пространство
+	Unit        string        // единица оценок
+	WeekStart   *time.Time    // понедельник недели (nil = все открытые задачи)
+	Members     []CapacityRow // участники с ролью member+
+}
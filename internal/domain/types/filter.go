@@ -0,0 +1,18 @@
+package types // пакет с моделями/типами
+
+type TaskFilter struct { // фильтры списка задач
+	Done        *bool    // nil = без фильтра (производное от статуса)
+	Status      *string  // nil = без фильтра
+	WorkspaceID *uint    // nil = все доступные пространства (и личные задачи)
+	Labels      []string // имена меток (пусто = без фильтра)
+	LabelMatch  string   // LabelMatchAny | LabelMatchAll
+	AssigneeID  *uint    // nil = без фильтра; задачи, где пользователь исполнитель
+	WatcherID   *uint    // nil = без фильтра; задачи, за которыми пользователь следит
+
+	FieldEquals map[string]string // ?cf[key]=value — сырые значения (нужен WorkspaceID)
+	SortBy      string            // ?sort=due_at | -created_at | cf.key (сырое)
+	FieldConds  []FieldCond       // разобранные FieldEquals (заполняет сервис)
+	Sort        *TaskSort         // разобранный SortBy (заполняет сервис, nil = по id)
+
+	IncludeArchived bool // показывать и архивные задачи
+}
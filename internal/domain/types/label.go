@@ -0,0 +1,22 @@
+package types // пакет с моделями/типами
+
+import "time" // time.Time
+
+const DefaultLabelColor = "#9e9e9e" // цвет метки по умолчанию
+
+type Label struct { // метка задачи (личная или пространства)
+	ID          uint      `gorm:"primaryKey"`                                                                                                // PK
+	UserID      uint      `gorm:"not null;index;uniqueIndex:idx_labels_personal_name,priority:1,where:workspace_id IS NULL"`                 // владелец личной метки / автор
+	WorkspaceID *uint     `gorm:"uniqueIndex:idx_labels_workspace_name,priority:1,where:workspace_id IS NOT NULL"`                           // пространство (nil = личная)
+	Name        string    `gorm:"not null;uniqueIndex:idx_labels_personal_name,priority:2;uniqueIndex:idx_labels_workspace_name,priority:2"` // имя (уникально у владельца)
+	Color       string    `gorm:"not null;default:'#9e9e9e'"`                                                                                // цвет #rrggbb
+	CreatedAt   time.Time // автозаполняется GORM
+	UpdatedAt   time.Time // автозаполняется GORM
+
+	Workspace *Workspace `gorm:"foreignKey:WorkspaceID;constraint:OnDelete:CASCADE"` // FK: метки уходят вместе с пространством
+}
+
+const ( // режим фильтра по меткам
+	LabelMatchAny = "any" // хотя бы одна из меток
+	LabelMatchAll = "all" // все метки сразу
+)
@@ -0,0 +1,14 @@
+package types // пакет с моделями/типами
+
+import "time" // time.Time
+
+type RefreshToken struct { // refresh-токен сессии (GORM)
+	ID        uint       `gorm:"primaryKey"`           // PK
+	UserID    uint       `gorm:"index;not null"`       // владелец
+	TokenHash string     `gorm:"uniqueIndex;not null"` // sha256 от токена (сам токен не храним)
+	ExpiresAt time.Time  `gorm:"not null"`             // срок действия
+	RevokedAt *time.Time // когда отозван (nil = активен)
+	CreatedAt time.Time  // автозаполняется GORM
+
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"` // FK: удаление пользователя гасит сессии
+}
@@ -0,0 +1,58 @@
+package types // пакет с моделями/типами
+
+import "time" // time.Time
+
+const ( // что произошло с задачей
+	RevisionCreate    = "create"    // создана
+	RevisionUpdate    = "update"    // изменена (PATCH, серия, метки, исполнители, наблюдатели, зависимости, чек-лист)
+	RevisionDelete    = "delete"    // удалена в корзину
+	RevisionRestore   = "restore"   // возвращена из корзины
+	RevisionArchive   = "archive"   // убрана в архив
+	RevisionUnarchive = "unarchive" // возвращена из архива
+	RevisionRevert    = "revert"    // откат к прошлой ревизии
+)
+
+type TaskSnapshot struct { // состояние задачи после ревизии (для отката)
+	Title        string              `json:"title"`                    // заголовок
+	Description  string              `json:"description"`              // описание
+	Priority     string              `json:"priority"`                 // приоритет
+	Status       string              `json:"status"`                   // статус процесса
+	Done         bool                `json:"done"`                     // выполнена
+	DueAt        *time.Time          `json:"due_at"`                   // срок
+	Estimate     *float64            `json:"estimate"`                 // оценка
+	ParentID     *uint               `json:"parent_id"`                // родитель
+	Recurrence   string              `json:"recurrence"`               // RRULE
+	Archived     bool                `json:"archived"`                 // в архиве (откатом не меняется)
+	LabelIDs     []uint              `json:"label_ids,omitempty"`      // метки (откатом не меняются)
+	AssigneeIDs  []uint              `json:"assignee_ids,omitempty"`   // исполнители (откатом не меняются)
+	WatcherIDs   []uint              `json:"watcher_ids,omitempty"`    // наблюдатели (откатом не меняются)
+	BlockedByIDs []uint              `json:"blocked_by_ids,omitempty"` // блокеры (откатом не меняются)
+	Checklist    []ChecklistSnapshot `json:"checklist,omitempty"`      // чек-лист по порядку (откатом не меняется)
+	CustomFields map[string]any      `json:"custom_fields,omitempty"`  // пользовательские поля по ключу
+}
+
+type ChecklistSnapshot struct { // пункт чек-листа в ревизии
+	ID    uint   `json:"id"`    // id пункта
+	Title string `json:"title"` // текст
+	Done  bool   `json:"done"`  // отмечен
+}
+
+type FieldChange struct { // изменение одного поля
+	Field  string `json:"field"`  // title, status, custom_fields.customer, ...
+	Before any    `json:"before"` // было (null = не задано)
+	After  any    `json:"after"`  // стало
+}
+
+type TaskRevision struct { // запись истории задачи
+	ID           uint          `gorm:"primaryKey"`     // PK
+	TaskID       uint          `gorm:"not null;index"` // задача
+	UserID       *uint         `gorm:"index"`          // кто изменил (nil — пользователь удалён или фоновая задача)
+	Action       string        `gorm:"not null"`       // RevisionCreate, RevisionUpdate, ...
+	RevertedFrom *uint         // к какой ревизии откатили (для RevisionRevert)
+	Changes      []FieldChange `gorm:"type:jsonb;serializer:json;not null;default:'[]'"` // что поменялось
+	Snapshot     TaskSnapshot  `gorm:"type:jsonb;serializer:json;not null"`              // состояние после
+	CreatedAt    time.Time     `gorm:"index"`                                            // когда
+
+	Task *Task `gorm:"foreignKey:TaskID;constraint:OnDelete:CASCADE"`  // FK: история стирается вместе с задачей
+	User *User `gorm:"foreignKey:UserID;constraint:OnDelete:SET NULL"` // FK: автор (email в истории)
+}
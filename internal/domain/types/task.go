@@ -1,11 +1,153 @@
 package types // пакет с моделями/типами
 
-import "time" // time.Time
+import (
+	"time" // time.Time
+
+	"gorm.io/gorm" // DeletedAt (мягкое удаление)
+)
+
+const ( // приоритеты задачи
+	PriorityLow    = "low"    // когда-нибудь
+	PriorityMedium = "medium" // по умолчанию
+	PriorityHigh   = "high"   // в первую очередь
+	PriorityUrgent = "urgent" // бросить всё
+)
+
+var TaskPriorities = []string{PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent} // допустимые приоритеты
 
 type Task struct { // модель задачи (GORM)
-	ID        uint      `gorm:"primaryKey"`             // PK
-	UserID    uint      `gorm:"index;not null"`         // FK на пользователя + индекс
-	Title     string    `gorm:"not null"`               // заголовок обязателен
-	Done      bool      `gorm:"not null;default:false"` // флаг выполнения
-	CreatedAt time.Time // автозаполняется GORM
+	ID          uint       `gorm:"primaryKey"`                    // PK
+	UserID      uint       `gorm:"index;not null"`                // создатель (FK на пользователя + индекс)
+	WorkspaceID *uint      `gorm:"index"`                         // пространство (nil = личная задача)
+	ParentID    *uint      `gorm:"index"`                         // родительская задача (nil = верхний уровень)
+	Title       string     `gorm:"not null"`                      // заголовок обязателен
+	Description string     `gorm:"type:text;not null;default:''"` // описание (markdown)
+	Priority    string     `gorm:"not null;default:'medium'"`     // приоритет (TaskPriorities)
+	DueAt       *time.Time `gorm:"index"`                         // срок (опц.)
+	Estimate    *float64   // оценка в единицах пространства (nil = не оценена)
+	Status      string     `gorm:"index;not null;default:'todo'"` // статус процесса (Workflow)
+	Done        bool       `gorm:"not null;default:false"`        // выполнена (производное от статуса, для ?done=)
+	CompletedAt *time.Time // когда выполнена (ставит/снимает сервис)
+	Version     uint       `gorm:"not null;default:1"` // растёт с каждым изменением (ETag / If-Match)
+	CreatedAt   time.Time  // автозаполняется GORM
+	UpdatedAt   time.Time  // автозаполняется GORM
+
+	Recurrence       string     `gorm:"not null;default:''"` // RRULE без DTSTART ("" = не повторяется)
+	RecurrenceStart  *time.Time // DTSTART серии (срок первого вхождения; нужен для COUNT и BYMONTHDAY)
+	NextOccurrenceID *uint      `gorm:"index"` // следующее вхождение (заполняет сервис при закрытии, защита от дублей)
+
+	ArchivedAt *time.Time     `gorm:"index"` // в архиве с (nil = в работе; списки по умолчанию прячут)
+	DeletedAt  gorm.DeletedAt `gorm:"index"` // в корзине с (GORM сам прячет такие строки из запросов)
+
+	Workspace *Workspace       `gorm:"foreignKey:WorkspaceID;constraint:OnDelete:CASCADE"`   // FK: задачи уходят вместе с пространством
+	Parent    *Task            `gorm:"foreignKey:ParentID;constraint:OnDelete:SET NULL"`     // FK: страховка — без родителя подзадача всплывает наверх
+	Labels    []Label          `gorm:"many2many:task_labels;constraint:OnDelete:CASCADE"`    // метки (task_labels, каскад с обеих сторон)
+	Assignees []User           `gorm:"many2many:task_assignees;constraint:OnDelete:CASCADE"` // исполнители (0..n)
+	Watchers  []User           `gorm:"many2many:task_watchers;constraint:OnDelete:CASCADE"`  // наблюдатели
+	Checklist []ChecklistItem  `gorm:"foreignKey:TaskID;constraint:OnDelete:CASCADE"`        // чек-лист (подгружается в карточке задачи)
+	Fields    []TaskFieldValue `gorm:"foreignKey:TaskID;constraint:OnDelete:CASCADE"`        // значения пользовательских полей (с Field)
+
+	Progress          *TaskProgress `gorm:"-"` // прогресс по подзадачам (заполняет repo, nil = подзадач нет)
+	BlockedBy         []TaskRef     `gorm:"-"` // от каких задач зависит (заполняет repo)
+	Blocking          []TaskRef     `gorm:"-"` // какие задачи ждут эту (заполняет repo)
+	CommentCount      int           `gorm:"-"` // сколько комментариев (заполняет repo)
+	ChecklistProgress *TaskProgress `gorm:"-"` // отмечено n из m пунктов (заполняет repo, nil = чек-листа нет)
+	TrackedSeconds    int64         `gorm:"-"` // учтённое время по завершённым записям (заполняет repo)
+}
+
+type TaskProgress struct { // n из m выполнено (подзадачи / пункты чек-листа)
+	Done  int // выполнено
+	Total int // всего
+}
+
+const ( // что делать с подзадачами при удалении родителя
+	DeleteChildrenReject  = "reject"  // не удалять, пока есть подзадачи
+	DeleteChildrenOrphan  = "orphan"  // подзадачи становятся задачами верхнего уровня
+	DeleteChildrenCascade = "cascade" // удалить всё поддерево
+)
+
+const ( // операции POST /tasks/bulk
+	BulkUpdate   = "update"    // частичное изменение полей
+	BulkDelete   = "delete"    // в корзину
+	BulkArchive  = "archive"   // в архив
+	BulkAddLabel = "add_label" // повесить метку
+	BulkReassign = "reassign"  // заменить исполнителей одним
+)
+
+type TaskPatch struct { // частичное изменение задачи (nil = не менять)
+	Title            *string    // новый заголовок
+	Description      *string    // новое описание
+	Priority         *string    // новый приоритет
+	DueAt            *time.Time // новый срок
+	ClearDueAt       bool       // снять срок
+	Status           *string    // новый статус процесса
+	Done             *bool      // выполнена (старые клиенты; сервис переводит в Status)
+	CompletedAt      *time.Time // момент выполнения
+	ClearCompletedAt bool       // снять момент выполнения
+	ParentID         *uint      // новый родитель
+	ClearParentID    bool       // сделать задачей верхнего уровня
+	Recurrence       *string    // новое правило повторения (нормализованный RRULE)
+	RecurrenceStart  *time.Time // начало серии (ставит сервис вместе с Recurrence)
+	ClearRecurrence  bool       // перестать повторять
+	Estimate         *float64   // новая оценка
+	ClearEstimate    bool       // снять оценку
+
+	CustomFields map[string]any // key -> значение пользовательского поля (nil = очистить; проверяет сервис)
+}
+
+func (p TaskPatch) Empty() bool { // нечего менять?
+	return p.Title == nil && p.Description == nil && p.Priority == nil &&
+		p.DueAt == nil && !p.ClearDueAt && p.Status == nil && p.Done == nil &&
+		p.ParentID == nil && !p.ClearParentID && p.Recurrence == nil && !p.ClearRecurrence &&
+		p.Estimate == nil && !p.ClearEstimate && len(p.CustomFields) == 0
+}
+
+func (p TaskPatch) Apply(t *Task) { // применить к модели
+	if p.Title != nil {
+		t.Title = *p.Title
+	}
+	if p.Description != nil {
+		t.Description = *p.Description
+	}
+	if p.Priority != nil {
+		t.Priority = *p.Priority
+	}
+	if p.DueAt != nil {
+		t.DueAt = p.DueAt
+	}
+	if p.ClearDueAt {
+		t.DueAt = nil
+	}
+	if p.Status != nil {
+		t.Status = *p.Status
+	}
+	if p.Done != nil {
+		t.Done = *p.Done
+	}
+	if p.CompletedAt != nil {
+		t.CompletedAt = p.CompletedAt
+	}
+	if p.ClearCompletedAt {
+		t.CompletedAt = nil
+	}
+	if p.ParentID != nil {
+		t.ParentID = p.ParentID
+	}
+	if p.ClearParentID {
+		t.ParentID = nil
+	}
+	if p.Recurrence != nil {
+		t.Recurrence = *p.Recurrence
+		t.RecurrenceStart = p.RecurrenceStart
+	}
+	if p.ClearRecurrence {
+		t.Recurrence = ""
+		t.RecurrenceStart = nil
+	}
+	if p.Estimate != nil {
+		t.Estimate = p.Estimate
+	}
+	if p.ClearEstimate {
+		t.Estimate = nil
+	}
 }
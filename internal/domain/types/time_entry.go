@@ -0,0 +1,53 @@
+package types // пакет с моделями/типами
+
+import "time" // time.Time
+
+type TimeEntry struct { // учёт времени по задаче (таймер или ручная запись)
+	ID        uint       `gorm:"primaryKey"`                                                                 // PK
+	TaskID    uint       `gorm:"not null;index"`                                                             // задача
+	UserID    uint       `gorm:"not null;index;uniqueIndex:idx_time_entries_running,where:ended_at IS NULL"` // кто работал (один запущенный таймер на пользователя)
+	StartedAt time.Time  `gorm:"not null;index"`                                                             // начало (день отчёта)
+	EndedAt   *time.Time // конец (nil = таймер идёт)
+	Seconds   int64      `gorm:"not null;default:0"`            // длительность (0, пока таймер идёт)
+	Note      string     `gorm:"type:text;not null;default:''"` // что делали
+	CreatedAt time.Time  // автозаполняется GORM
+	UpdatedAt time.Time  // автозаполняется GORM
+
+	Task *Task `gorm:"foreignKey:TaskID;constraint:OnDelete:CASCADE"` // FK: записи уходят вместе с задачей
+	User *User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"` // FK: автор записи
+}
+
+func (e *TimeEntry) Running() bool { return e.EndedAt == nil } // таймер ещё идёт?
+
+type TimeReportFilter struct { // параметры отчёта по времени
+	From        time.Time // начало периода (включительно)
+	To          time.Time // конец периода (не включительно)
+	WorkspaceID *uint     // одно пространство (nil = все видимые задачи)
+	UserID      *uint     // один пользователь
+}
+
+type TimeReport struct { // агрегаты за период (только завершённые записи)
+	From         time.Time     // начало периода (с учётом дефолтов)
+	To           time.Time     // конец периода (не включительно)
+	TotalSeconds int64         // всего
+	ByUser       []TimeByUser  // по пользователям
+	ByLabel      []TimeByLabel // по меткам (запись с двумя метками считается в обеих)
+	ByDay        []TimeByDay   // по дням начала записи (UTC)
+}
+
+type TimeByUser struct { // строка отчёта по пользователю
+	UserID  uint   // пользователь
+	Email   string // email
+	Seconds int64  // сумма
+}
+
+type TimeByLabel struct { // строка отчёта по метке
+	LabelID uint   // метка
+	Name    string // имя
+	Seconds int64  // сумма
+}
+
+type TimeByDay struct { // строка отчёта по дню
+	Day     time.Time // полночь UTC
+	Seconds int64     // сумма
+}
@@ -2,10 +2,18 @@ package types // пакет с моделями/типами
 
 import "time" // time.Time
 
+const ( // роли пользователя
+	RoleUser  = "user"  // обычный пользователь: видит только своё
+	RoleAdmin = "admin" // поддержка: обходит ограничение по владельцу
+)
+
 type User struct { // модель пользователя (GORM)
-	ID        uint      `gorm:"primaryKey"`            // PK
-	Email     string    `gorm:"uniqueIndex;not null"`  // уникальный email, обязателен
-	CreatedAt time.Time                                // автозаполняется GORM
+	ID           uint      `gorm:"primaryKey"`              // PK
+	Email        string    `gorm:"uniqueIndex;not null"`    // уникальный email, обязателен
+	PasswordHash string    `gorm:"not null;default:''"`     // bcrypt-хеш пароля (пусто = вход запрещён)
+	Role         string    `gorm:"not null;default:'user'"` // роль (RoleUser / RoleAdmin)
+	CreatedAt    time.Time // автозаполняется GORM
+	UpdatedAt    time.Time // автозаполняется GORM
 
 	Tasks []Task `gorm:"foreignKey:UserID"` // связь 1->many по UserID
 }
@@ -0,0 +1,89 @@
+package types // пакет с моделями/типами
+
+import "time" // time.Time
+
+const ( // статусы дефолтного процесса
+	StatusTodo       = "todo"        // к выполнению
+	StatusInProgress = "in_progress" // в работе
+	StatusDone       = "done"        // выполнена
+)
+
+type WorkflowStatus struct { // статус процесса
+	Key  string `json:"key"`  // машинное имя (хранится в tasks.status)
+	Name string `json:"name"` // отображаемое имя
+	Done bool   `json:"done"` // считается выполненной (tasks.done = true)
+}
+
+type WorkflowTransition struct { // разрешённый переход
+	From string `json:"from"` // из статуса
+	To   string `json:"to"`   // в статус
+}
+
+type Workflow struct { // процесс пространства (GORM)
+	WorkspaceID uint                 `gorm:"primaryKey"`                          // PK + FK на пространство
+	Initial     string               `gorm:"not null"`                            // статус новых задач
+	Statuses    []WorkflowStatus     `gorm:"type:jsonb;serializer:json;not null"` // статусы по порядку
+	Transitions []WorkflowTransition `gorm:"type:jsonb;serializer:json;not null"` // разрешённые переходы
+	UpdatedAt   time.Time            // автозаполняется GORM
+
+	Workspace Workspace `gorm:"foreignKey:WorkspaceID;constraint:OnDelete:CASCADE"` // FK: уходит вместе с пространством
+}
+
+func DefaultWorkflow() *Workflow { // todo -> in_progress -> done (+ быстрые закрыть/переоткрыть)
+	return &Workflow{
+		Initial: StatusTodo,
+		Statuses: []WorkflowStatus{
+			{Key: StatusTodo, Name: "To do"},
+			{Key: StatusInProgress, Name: "In progress"},
+			{Key: StatusDone, Name: "Done", Done: true},
+		},
+		Transitions: []WorkflowTransition{
+			{From: StatusTodo, To: StatusInProgress},
+			{From: StatusInProgress, To: StatusTodo},
+			{From: StatusInProgress, To: StatusDone},
+			{From: StatusDone, To: StatusInProgress},
+			{From: StatusTodo, To: StatusDone}, // PATCH {"done": true} у старых клиентов
+			{From: StatusDone, To: StatusTodo}, // PATCH {"done": false}
+		},
+	}
+}
+
+func (w *Workflow) Status(key string) (WorkflowStatus, bool) { // статус по ключу
+	for _, s := range w.Statuses {
+		if s.Key == key {
+			return s, true
+		}
+	}
+	return WorkflowStatus{}, false
+}
+
+func (w *Workflow) CanTransition(from, to string) bool { // разрешён ли переход
+	if from == to { // остаться на месте можно всегда
+		return true
+	}
+	for _, t := range w.Transitions {
+		if t.From == from && t.To == to {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Workflow) NextStatuses(from string) []string { // куда можно перейти
+	next := []string{}
+	for _, t := range w.Transitions {
+		if t.From == from {
+			next = append(next, t.To)
+		}
+	}
+	return next
+}
+
+func (w *Workflow) DoneStatus() string { // первый "выполненный" статус (для done=true)
+	for _, s := range w.Statuses {
+		if s.Done {
+			return s.Key
+		}
+	}
+	return ""
+}
@@ -0,0 +1,86 @@
+package types // пакет с моделями/типами
+
+import "time" // time.Time
+
+const ( // роли участника рабочего пространства
+	WorkspaceRoleOwner  = "owner"  // полный контроль, удаление пространства
+	WorkspaceRoleAdmin  = "admin"  // управление участниками и приглашениями
+	WorkspaceRoleMember = "member" // работа с задачами
+	WorkspaceRoleViewer = "viewer" // только чтение
+)
+
+func WorkspaceRoleRank(role string) int { // сравнение ролей: больше = больше прав
+	switch role {
+	case WorkspaceRoleOwner:
+		return 4
+	case WorkspaceRoleAdmin:
+		return 3
+	case WorkspaceRoleMember:
+		return 2
+	case WorkspaceRoleViewer:
+		return 1
+	}
+	return 0 // неизвестная роль
+}
+
+const ( // в чём оцениваются задачи
+	EstimateUnitPoints = "points" // story points (по умолчанию, и для личных задач)
+	EstimateUnitHours  = "hours"  // часы
+)
+
+type Workspace struct { // рабочее пространство команды (GORM)
+	ID             uint      `gorm:"primaryKey"`                // PK
+	Name           string    `gorm:"not null"`                  // название
+	EstimateUnit   string    `gorm:"not null;default:'points'"` // единица оценок задач
+	WeeklyCapacity float64   `gorm:"not null;default:0"`        // ёмкость участника в неделю, в EstimateUnit (0 = не задана)
+	CreatedAt      time.Time // автозаполняется GORM
+	UpdatedAt      time.Time // автозаполняется GORM
+
+	Members []WorkspaceMember `gorm:"foreignKey:WorkspaceID;constraint:OnDelete:CASCADE"` // участники
+}
+
+type WorkspaceMember struct { // участник пространства (GORM)
+	WorkspaceID    uint      `gorm:"primaryKey"`                // PK + FK на пространство
+	UserID         uint      `gorm:"primaryKey;index"`          // PK + FK на пользователя
+	Role           string    `gorm:"not null;default:'member'"` // роль в пространстве
+	WeeklyCapacity *float64  // своя ёмкость в неделю (nil = как у пространства)
+	CreatedAt      time.Time // когда вступил
+
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"` // FK: удаление пользователя убирает членство
+}
+
+type WorkspacePatch struct { // частичное изменение пространства (nil = не менять)
+	Name           *string  // новое название
+	EstimateUnit   *string  // новая единица оценок (сами оценки не пересчитываются)
+	WeeklyCapacity *float64 // новая ёмкость по умолчанию
+}
+
+func (p WorkspacePatch) Empty() bool { // нечего менять?
+	return p.Name == nil && p.EstimateUnit == nil && p.WeeklyCapacity == nil
+}
+
+func (p WorkspacePatch) Apply(ws *Workspace) { // применить к модели
+	if p.Name != nil {
+		ws.Name = *p.Name
+	}
+	if p.EstimateUnit != nil {
+		ws.EstimateUnit = *p.EstimateUnit
+	}
+	if p.WeeklyCapacity != nil {
+		ws.WeeklyCapacity = *p.WeeklyCapacity
+	}
+}
+
+type WorkspaceInvitation struct { // приглашение по email (GORM)
+	ID          uint       `gorm:"primaryKey"`                // PK
+	WorkspaceID uint       `gorm:"index;not null"`            // куда приглашают
+	Email       string     `gorm:"not null"`                  // кого приглашают
+	Role        string     `gorm:"not null;default:'member'"` // роль после принятия
+	TokenHash   string     `gorm:"uniqueIndex;not null"`      // sha256 от токена (сам токен не храним)
+	InvitedByID uint       `gorm:"not null"`                  // кто пригласил
+	ExpiresAt   time.Time  `gorm:"not null"`                  // срок действия
+	AcceptedAt  *time.Time // когда принято (nil = ожидает)
+	CreatedAt   time.Time  // автозаполняется GORM
+
+	Workspace Workspace `gorm:"foreignKey:WorkspaceID;constraint:OnDelete:CASCADE"` // FK: удаление пространства гасит приглашения
+}
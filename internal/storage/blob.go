@@ -0,0 +1,28 @@
+package storage // хранилища содержимого файлов (вложения)
+
+import (
+	"context" // ctx
+	"errors"  // errors.New
+	"io"      // потоки
+	"strings" // проверка ключа
+)
+
+var ErrBlobNotFound = errors.New("blob not found") // объекта нет
+
+type BlobStore interface { // куда складываем байты вложений (метаданные — в БД)
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error // записать (перезаписывает)
+	Get(ctx context.Context, key string) (io.ReadCloser, error)                             // открыть на чтение (ErrBlobNotFound)
+	Delete(ctx context.Context, key string) error                                           // удалить (нет — не ошибка)
+}
+
+func validKey(key string) bool { // ключ вида "tasks/1/abc": без пустых сегментов и ".."
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
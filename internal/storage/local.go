@@ -0,0 +1,83 @@
+package storage // хранилища содержимого файлов (вложения)
+
+import (
+	"context"       // ctx
+	"errors"        // errors.Is
+	"fmt"           // ошибки
+	"io"            // потоки
+	"io/fs"         // fs.ErrNotExist
+	"os"            // файлы
+	"path/filepath" // пути
+)
+
+type LocalBlobStore struct { // файлы на локальном диске
+	dir string // корневая папка
+}
+
+func NewLocalBlobStore(dir string) (*LocalBlobStore, error) { // конструктор (создаёт папку)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("blob dir: %w", err)
+	}
+	return &LocalBlobStore{dir: dir}, nil
+}
+
+func (s *LocalBlobStore) path(key string) (string, error) { // ключ -> путь внутри dir
+	if !validKey(key) {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
+
+func (s *LocalBlobStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error { // записать атомарно
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o750); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".upload-*") // пишем рядом, потом rename
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // после rename — no-op
+
+	n, err := io.Copy(tmp, r)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if size >= 0 && n != size { // обрезанный поток
+		return fmt.Errorf("blob size mismatch: want %d, got %d", size, n)
+	}
+	if err := ctx.Err(); err != nil { // клиент ушёл — не публикуем
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+func (s *LocalBlobStore) Get(_ context.Context, key string) (io.ReadCloser, error) { // открыть файл
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrBlobNotFound
+	}
+	return f, err
+}
+
+func (s *LocalBlobStore) Delete(_ context.Context, key string) error { // удалить файл
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
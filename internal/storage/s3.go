@@ -0,0 +1,183 @@
+package storage // хранилища содержимого файлов (вложения)
+
+import (
+	"context"       // ctx
+	"crypto/hmac"   // подпись
+	"crypto/sha256" // подпись
+	"encoding/hex"  // hex
+	"fmt"           // ошибки
+	"io"            // потоки
+	"net/http"      // HTTP-клиент
+	"net/url"       // endpoint
+	"strconv"       // Content-Length
+	"strings"       // сборка строк
+	"time"          // x-amz-date
+)
+
+const ( // константы SigV4
+	s3Algorithm       = "AWS4-HMAC-SHA256"
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"                                                 // тело не хешируем (стримим как есть)
+	s3EmptySHA256     = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855" // sha256("")
+)
+
+type S3Config struct { // параметры S3-совместимого хранилища (AWS, MinIO...)
+	Endpoint  string // https://s3.eu-central-1.amazonaws.com или http://minio:9000
+	Region    string // us-east-1 для MinIO
+	Bucket    string // бакет (должен существовать)
+	AccessKey string // ключ доступа
+	SecretKey string // секрет
+}
+
+type S3BlobStore struct { // объекты в S3 (path-style, подпись SigV4)
+	cfg    S3Config     // параметры
+	base   *url.URL     // разобранный endpoint
+	client *http.Client // HTTP-клиент
+}
+
+func NewS3BlobStore(cfg S3Config) (*S3BlobStore, error) { // конструктор
+	base, err := url.Parse(strings.TrimRight(cfg.Endpoint, "/"))
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		return nil, fmt.Errorf("S3 endpoint must be an absolute URL")
+	}
+	if cfg.Bucket == "" || cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, fmt.Errorf("S3 bucket and credentials are required")
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	return &S3BlobStore{cfg: cfg, base: base, client: &http.Client{Timeout: 5 * time.Minute}}, nil
+}
+
+func (s *S3BlobStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error { // PUT объекта
+	req, err := s.newRequest(ctx, http.MethodPut, key, r)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size // S3 не принимает chunked без aws-chunked
+	req.Header.Set("Content-Length", strconv.FormatInt(size, 10))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	s.sign(req, s3UnsignedPayload)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return s3Error("put", resp)
+	}
+	return nil
+}
+
+func (s *S3BlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) { // GET объекта (тело закрывает вызывающий)
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	s.sign(req, s3EmptySHA256)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrBlobNotFound
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		return nil, s3Error("get", resp)
+	}
+	return resp.Body, nil
+}
+
+func (s *S3BlobStore) Delete(ctx context.Context, key string) error { // DELETE объекта (S3 отвечает 204 и на отсутствующий)
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	s.sign(req, s3EmptySHA256)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 && resp.StatusCode != http.StatusNotFound {
+		return s3Error("delete", resp)
+	}
+	return nil
+}
+
+func (s *S3BlobStore) newRequest(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) { // запрос к /bucket/key
+	if !validKey(key) {
+		return nil, fmt.Errorf("invalid blob key %q", key)
+	}
+	u := *s.base
+	u.Path = s.base.Path + "/" + s.cfg.Bucket + "/" + key                        // path-style: работает и с MinIO
+	u.RawPath = s.base.Path + "/" + s3Escape(s.cfg.Bucket) + "/" + s3Escape(key) // как будет подписано
+	return http.NewRequestWithContext(ctx, method, u.String(), body)
+}
+
+func (s *S3BlobStore) sign(req *http.Request, payloadHash string) { // AWS Signature Version 4
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonical := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery, // у нас всегда пусто
+		"host:" + req.URL.Host + "\n" +
+			"x-amz-content-sha256:" + payloadHash + "\n" +
+			"x-amz-date:" + amzDate + "\n",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := day + "/" + s.cfg.Region + "/s3/aws4_request"
+	hashed := sha256.Sum256([]byte(canonical))
+	toSign := s3Algorithm + "\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hashed[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretKey), day) // ключ подписи: дата -> регион -> сервис
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, toSign))
+
+	req.Header.Set("Authorization", s3Algorithm+
+		" Credential="+s.cfg.AccessKey+"/"+scope+
+		", SignedHeaders="+signedHeaders+
+		", Signature="+signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte { // HMAC-SHA256
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte(data))
+	return m.Sum(nil)
+}
+
+func s3Escape(path string) string { // URI-кодирование по правилам SigV4 ("/" остаётся)
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if c == '/' || c == '-' || c == '_' || c == '.' || c == '~' ||
+			('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func s3Error(op string, resp *http.Response) error { // ошибка S3 с кусочком XML для логов
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("s3 %s: %s: %s", op, resp.Status, strings.TrimSpace(string(body)))
+}
@@ -0,0 +1,212 @@
+package storage // хранилища содержимого файлов (вложения)
+
+import (
+	"context"           // ctx
+	"crypto/hmac"       // проверка подписи
+	"crypto/sha256"     // проверка подписи
+	"encoding/hex"      // hex
+	"errors"            // errors.Is
+	"io"                // тела
+	"net/http"          // HTTP
+	"net/http/httptest" // локальный S3
+	"strings"           // разбор заголовков
+	"sync"              // объекты сервера
+	"testing"           // тесты
+	"time"              // x-amz-date
+)
+
+const ( // учётка тестового сервера
+	testS3AccessKey = "AKIDEXAMPLE"
+	testS3SecretKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+	testS3Region    = "eu-central-1"
+	testS3Bucket    = "attachments"
+)
+
+type fakeS3 struct { // S3 в памяти: проверяет SigV4 каждого запроса, как настоящий
+	t       *testing.T
+	mu      sync.Mutex
+	objects map[string][]byte // путь запроса -> содержимое
+	types   map[string]string // путь запроса -> Content-Type
+	fail    int               // != 0 — ответить этим статусом на следующий запрос
+
+	canonical string // canonical request последнего запроса (как его собрал сервер)
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *S3BlobStore) { // сервер + клиент к нему
+	f := &fakeS3{t: t, objects: map[string][]byte{}, types: map[string]string{}}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	store, err := NewS3BlobStore(S3Config{Endpoint: srv.URL, Region: testS3Region, Bucket: testS3Bucket, AccessKey: testS3AccessKey, SecretKey: testS3SecretKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f, store
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.verify(r); err != nil {
+		f.t.Errorf("%s %s: %v", r.Method, r.RequestURI, err)
+		http.Error(w, "<Error><Code>SignatureDoesNotMatch</Code></Error>", http.StatusForbidden)
+		return
+	}
+	if f.fail != 0 {
+		status := f.fail
+		f.fail = 0
+		http.Error(w, "<Error><Code>InternalError</Code></Error>", status)
+		return
+	}
+
+	path := r.URL.EscapedPath()
+	switch r.Method {
+	case http.MethodPut:
+		if r.Header.Get("X-Amz-Content-Sha256") != s3UnsignedPayload {
+			f.t.Errorf("PUT must be sent with UNSIGNED-PAYLOAD, got %q", r.Header.Get("X-Amz-Content-Sha256"))
+		}
+		if len(r.TransferEncoding) > 0 {
+			f.t.Errorf("PUT must not be chunked: %v", r.TransferEncoding)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if int64(len(body)) != r.ContentLength {
+			f.t.Errorf("Content-Length %d, body %d bytes", r.ContentLength, len(body))
+		}
+		f.objects[path] = body
+		f.types[path] = r.Header.Get("Content-Type")
+	case http.MethodGet:
+		body, ok := f.objects[path]
+		if !ok {
+			http.Error(w, "<Error><Code>NoSuchKey</Code></Error>", http.StatusNotFound)
+			return
+		}
+		w.Write(body)
+	case http.MethodDelete:
+		delete(f.objects, path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *fakeS3) verify(r *http.Request) error { // SigV4 по документации AWS, независимо от клиента
+	amzDate := r.Header.Get("X-Amz-Date")
+	at, err := time.Parse("20060102T150405Z", amzDate)
+	if err != nil {
+		return errors.New("bad X-Amz-Date " + amzDate)
+	}
+	if d := time.Since(at); d > time.Minute || d < -time.Minute {
+		return errors.New("stale X-Amz-Date " + amzDate)
+	}
+	payload := r.Header.Get("X-Amz-Content-Sha256")
+	if payload == "" {
+		return errors.New("missing X-Amz-Content-Sha256")
+	}
+
+	day := amzDate[:8]
+	scope := day + "/" + testS3Region + "/s3/aws4_request"
+	const signed = "host;x-amz-content-sha256;x-amz-date"
+	prefix := s3Algorithm + " Credential=" + testS3AccessKey + "/" + scope + ", SignedHeaders=" + signed + ", Signature="
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, prefix) {
+		return errors.New("unexpected Authorization " + auth)
+	}
+
+	path, query, _ := strings.Cut(r.RequestURI, "?") // ровно то, что пришло по сети
+	f.canonical = r.Method + "\n" + path + "\n" + query + "\n" +
+		"host:" + r.Host + "\n" + "x-amz-content-sha256:" + payload + "\n" + "x-amz-date:" + amzDate + "\n" + "\n" +
+		signed + "\n" + payload
+	hashed := sha256.Sum256([]byte(f.canonical))
+	toSign := s3Algorithm + "\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hashed[:])
+
+	key := []byte("AWS4" + testS3SecretKey)
+	for _, part := range []string{day, testS3Region, "s3", "aws4_request"} {
+		m := hmac.New(sha256.New, key)
+		m.Write([]byte(part))
+		key = m.Sum(nil)
+	}
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte(toSign))
+	if want := hex.EncodeToString(m.Sum(nil)); strings.TrimPrefix(auth, prefix) != want {
+		return errors.New("signature mismatch, canonical request:\n" + f.canonical)
+	}
+	return nil
+}
+
+func TestS3PutGetDelete(t *testing.T) {
+	f, store := newFakeS3(t)
+	ctx := context.Background()
+	key := "tasks/1/a b+ü.txt" // пробел, плюс и не-ASCII — кодирование пути тоже подписывается
+
+	if err := store.Put(ctx, key, strings.NewReader("hello"), 5, "text/plain"); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	wantPath := "/" + testS3Bucket + "/tasks/1/a%20b%2B%C3%BC.txt"
+	if f.types[wantPath] != "text/plain" {
+		t.Fatalf("object not stored at %s (have %v)", wantPath, f.types)
+	}
+
+	rc, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	body, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(body) != "hello" {
+		t.Fatalf("get: body %q", body)
+	}
+	if !strings.HasPrefix(f.canonical, "GET\n"+wantPath+"\n\nhost:") || !strings.HasSuffix(f.canonical, "\n"+s3EmptySHA256) {
+		t.Fatalf("get: canonical request\n%s", f.canonical)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := store.Get(ctx, key); !errors.Is(err, ErrBlobNotFound) {
+		t.Fatalf("get after delete: want ErrBlobNotFound, got %v", err)
+	}
+	if err := store.Delete(ctx, key); err != nil { // повторное удаление — не ошибка
+		t.Fatalf("delete missing: %v", err)
+	}
+}
+
+func TestS3Errors(t *testing.T) {
+	f, store := newFakeS3(t)
+	ctx := context.Background()
+
+	f.fail = http.StatusInternalServerError
+	err := store.Put(ctx, "tasks/1/x", strings.NewReader("x"), 1, "")
+	if err == nil || !strings.Contains(err.Error(), "s3 put: 500") || !strings.Contains(err.Error(), "InternalError") {
+		t.Fatalf("put: want s3 error with status and body, got %v", err)
+	}
+
+	f.fail = http.StatusForbidden
+	if _, err := store.Get(ctx, "tasks/1/x"); err == nil || errors.Is(err, ErrBlobNotFound) {
+		t.Fatalf("get: 403 must not look like a missing blob, got %v", err)
+	}
+
+	f.fail = http.StatusServiceUnavailable
+	if err := store.Delete(ctx, "tasks/1/x"); err == nil || !strings.Contains(err.Error(), "s3 delete: 503") {
+		t.Fatalf("delete: want s3 error, got %v", err)
+	}
+
+	if err := store.Put(ctx, "../etc/passwd", strings.NewReader("x"), 1, ""); err == nil { // до сети не доходит
+		t.Fatal("put: invalid key accepted")
+	}
+}
+
+func TestS3Config(t *testing.T) {
+	if _, err := NewS3BlobStore(S3Config{Endpoint: "minio:9000", Bucket: "b", AccessKey: "a", SecretKey: "s"}); err == nil {
+		t.Fatal("relative endpoint accepted")
+	}
+	if _, err := NewS3BlobStore(S3Config{Endpoint: "http://minio:9000", AccessKey: "a", SecretKey: "s"}); err == nil {
+		t.Fatal("missing bucket accepted")
+	}
+	store, err := NewS3BlobStore(S3Config{Endpoint: "http://minio:9000/", Bucket: "b", AccessKey: "a", SecretKey: "s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.cfg.Region != "us-east-1" { // дефолт MinIO
+		t.Fatalf("default region %q", store.cfg.Region)
+	}
+}